@@ -1,62 +1,293 @@
 package main
 
 import (
+	"bufio"
+	"context"
 	"flag"
 	"fmt"
+	"log/slog"
 	"os"
+	"strings"
 
 	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript"
+	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_cache"
 	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_formatters"
+	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_models"
 )
 
 func main() {
 	var (
 		languages                = flag.String("languages", "en", "Comma-separated list of language codes")
-		formatter                = flag.String("formatter", "json", "Formatter to use (json, text)")
+		formatter                = flag.String("formatter", "json", "Formatter to use (json, text, srt, vtt)")
 		preserve_formatting      = flag.Bool("preserve_formatting", true, "Preserve formatting")
 		with_timestamps          = flag.Bool("with_timestamps", true, "Include timestamps")
 		with_language_code       = flag.Bool("with_language_code", true, "Include language code")
-		exclude_manually_created = flag.Bool("exclude_manually_created", false, "Exclude manually created subtitles") // not in use yet
-		exclude_auto_generated   = flag.Bool("exclude_auto_generated", false, "Exclude auto-generated subtitles")     // not in use yet
+		exclude_manually_created = flag.Bool("exclude_manually_created", false, "Exclude manually created subtitles")
+		exclude_auto_generated   = flag.Bool("exclude_auto_generated", false, "Exclude auto-generated subtitles")
+		prefer_manual            = flag.Bool("prefer_manual", false, "Use manually created subtitles when available, auto-generated otherwise")
+		translate                = flag.String("translate", "", "Language code to translate the transcript into")
+		language_fallback        = flag.Bool("language_fallback", false, "Only fetch the first available language from -languages")
+		input                    = flag.String("input", "", "File with one video ID or URL per line")
+		concurrency              = flag.Int("concurrency", 4, "Number of videos fetched concurrently")
+		playlist                 = flag.String("playlist", "", "Playlist ID or URL whose videos are fetched")
+		channel                  = flag.String("channel", "", "Channel handle, ID or URL whose uploads are fetched")
+		cache_dir                = flag.String("cache_dir", "", "Directory caching track lists and captions between runs")
+		proxy                    = flag.String("proxy", "", "Comma-separated list of http(s) or socks5 proxy URLs, rotated when more than one")
+		proxy_strategy           = flag.String("proxy_strategy", "round_robin", "Proxy rotation strategy (round_robin, random)")
+		rate_limit               = flag.Float64("rate_limit", 0, "Maximum requests per second sent to YouTube, 0 for no limit")
+		caption_format           = flag.String("caption_format", "xml", "Caption format to download (xml, srv3, json3), srv3 and json3 add word timing to auto-generated tracks")
+		rich_text                = flag.String("rich_text", "", "Render bold, italic and underline of text and srt output as markdown or ansi")
+		cookies                  = flag.String("cookies", "", "Netscape-format cookies.txt file of a signed in account")
+		verbose                  = flag.Bool("verbose", false, "Log requests, retries and cache activity to stderr")
 	)
 	flag.Parse()
 
-	if flag.NArg() < 1 {
-		fmt.Println("Please provide at least one video ID")
+	videoIDs := flag.Args()
+	if *input != "" {
+		fileIDs, err := readVideoIDs(*input)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		videoIDs = append(videoIDs, fileIDs...)
+	}
+
+	if len(videoIDs) < 1 && *playlist == "" && *channel == "" {
+		fmt.Fprintln(os.Stderr, "Please provide at least one video ID")
 		os.Exit(1)
 	}
 
 	if *exclude_manually_created && *exclude_auto_generated {
-		fmt.Println("Cannot exclude both manually created and auto-generated subtitles")
+		fmt.Fprintln(os.Stderr, "Cannot exclude both manually created and auto-generated subtitles")
+		os.Exit(1)
+	}
+
+	var spanRenderer yt_transcript_formatters.SpanRenderer
+	switch *rich_text {
+	case "":
+	case "markdown":
+		spanRenderer = yt_transcript_formatters.MarkdownSpans
+	case "ansi":
+		spanRenderer = yt_transcript_formatters.ANSISpans
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown rich text rendering %q\n", *rich_text)
 		os.Exit(1)
 	}
 
-	var outputFormatter yt_transcript_formatters.Formatter
+	var outputFormatter yt_transcript_formatters.StreamingFormatter
 
-	if *formatter == "text" {
+	switch *formatter {
+	case "text":
 		outputFormatter = yt_transcript_formatters.NewTextFormatter(
 			yt_transcript_formatters.WithTimestamps(*with_timestamps),
 			yt_transcript_formatters.WithLanguageCode(*with_language_code),
+			yt_transcript_formatters.WithSpanRenderer(spanRenderer),
 		)
-	} else {
+	case "srt":
+		outputFormatter = yt_transcript_formatters.NewSRTFormatter(
+			yt_transcript_formatters.WithSpanRenderer(spanRenderer),
+		)
+	case "vtt":
+		outputFormatter = yt_transcript_formatters.NewWebVTTFormatter(
+			yt_transcript_formatters.WithLanguageCode(*with_language_code),
+		)
+	default:
 		outputFormatter = yt_transcript_formatters.NewJSONFormatter(
 			yt_transcript_formatters.WithTimestamps(*with_timestamps),
 			yt_transcript_formatters.WithLanguageCode(*with_language_code),
 		)
 	}
 
-	client := yt_transcript.NewClient(
+	trackSelection := yt_transcript.AnyTrack
+	switch {
+	case *exclude_manually_created:
+		trackSelection = yt_transcript.GeneratedOnly
+	case *exclude_auto_generated:
+		trackSelection = yt_transcript.ManualOnly
+	case *prefer_manual:
+		trackSelection = yt_transcript.PreferManual
+	}
+
+	var captionFormat yt_transcript.CaptionFormat
+	switch *caption_format {
+	case "xml":
+		captionFormat = yt_transcript.CaptionFormatXML
+	case "srv3":
+		captionFormat = yt_transcript.CaptionFormatSrv3
+	case "json3":
+		captionFormat = yt_transcript.CaptionFormatJSON3
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown caption format %q\n", *caption_format)
+		os.Exit(1)
+	}
+
+	clientOptions := []yt_transcript.Option{
 		yt_transcript.WithFormatter(outputFormatter),
-	)
+		yt_transcript.WithTrackSelection(trackSelection),
+		yt_transcript.WithLanguageFallback(*language_fallback),
+		yt_transcript.WithCaptionFormat(captionFormat),
+		yt_transcript.WithSpans(spanRenderer != nil),
+	}
+
+	if *verbose {
+		logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		clientOptions = append(clientOptions, yt_transcript.WithLogger(logger))
+	}
+
+	if *cache_dir != "" {
+		cache, err := yt_transcript_cache.NewFileCache(*cache_dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		clientOptions = append(clientOptions, yt_transcript.WithCache(cache))
+	}
+
+	if *cookies != "" {
+		jar, err := yt_transcript.LoadCookieJar(*cookies)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		clientOptions = append(clientOptions, yt_transcript.WithCookieJar(jar))
+	}
+
+	if *rate_limit > 0 {
+		clientOptions = append(clientOptions, yt_transcript.WithRateLimit(*rate_limit, 1))
+	}
+
+	if *proxy != "" {
+		proxyOption, err := proxyClientOption(strings.Split(*proxy, ","), *proxy_strategy)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		clientOptions = append(clientOptions, proxyOption)
+	}
+
+	client := yt_transcript.NewClient(clientOptions...)
+
+	languageList := strings.Split(*languages, ",")
+
+	if *playlist != "" {
+		playlistIDs, err := client.GetPlaylistVideoIDs(context.Background(), *playlist)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		videoIDs = append(videoIDs, playlistIDs...)
+	}
+
+	if *channel != "" {
+		channelIDs, err := client.GetChannelVideoIDs(context.Background(), *channel)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		videoIDs = append(videoIDs, channelIDs...)
+	}
 
-	videoID := flag.Arg(0)
-	t, err := client.GetFormattedTranscripts(videoID, []string{*languages}, *preserve_formatting)
+	out := bufio.NewWriter(os.Stdout)
+
+	var err error
+	if len(videoIDs) > 1 {
+		err = writeBatch(client, out, outputFormatter, videoIDs,
+			yt_transcript.WithBatchLanguages(languageList...),
+			yt_transcript.WithBatchPreserveFormatting(*preserve_formatting),
+			yt_transcript.WithBatchTranslation(*translate),
+			yt_transcript.WithConcurrency(*concurrency),
+		)
+	} else if *translate != "" {
+		var transcripts []yt_transcript_models.Transcript
+		transcripts, err = client.GetTranslatedTranscripts(videoIDs[0], languageList, *translate, *preserve_formatting)
+		if err == nil {
+			err = outputFormatter.WriteTo(out, transcripts)
+		}
+	} else {
+		err = client.WriteFormattedTranscripts(out, videoIDs[0], languageList, *preserve_formatting)
+	}
+
+	if flushErr := out.Flush(); err == nil {
+		err = flushErr
+	}
 
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	fmt.Printf("%s", t)
 	os.Exit(0)
 }
+
+// writeBatch fetches all videos and writes the transcripts that succeeded as one document.
+// Failures are reported on stderr and turn into an error once everything has been written.
+func writeBatch(client *yt_transcript.YtTranscriptClient, out *bufio.Writer, formatter yt_transcript_formatters.StreamingFormatter, videoIDs []string, options ...yt_transcript.BatchOption) error {
+	results := client.GetTranscriptsBatch(context.Background(), videoIDs, options...)
+
+	var (
+		transcripts []yt_transcript_models.Transcript
+		failed      int
+	)
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "Error (%s): %v\n", result.VideoID, result.Err)
+			continue
+		}
+		transcripts = append(transcripts, result.Transcripts...)
+	}
+
+	if err := formatter.WriteTo(out, transcripts); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d videos failed", failed, len(videoIDs))
+	}
+	return nil
+}
+
+func proxyClientOption(proxyURLs []string, strategy string) (yt_transcript.Option, error) {
+	if len(proxyURLs) == 1 {
+		proxyURL, err := yt_transcript.ParseProxyURL(proxyURLs[0])
+		if err != nil {
+			return nil, err
+		}
+		return yt_transcript.WithProxy(proxyURL), nil
+	}
+
+	proxyStrategy := yt_transcript.RoundRobin
+	if strategy == "random" {
+		proxyStrategy = yt_transcript.RandomProxy
+	}
+
+	pool, err := yt_transcript.NewProxyPool(proxyURLs, yt_transcript.WithProxyStrategy(proxyStrategy))
+	if err != nil {
+		return nil, err
+	}
+	return yt_transcript.WithProxyPool(pool), nil
+}
+
+// readVideoIDs reads one video ID or URL per line, skipping blank lines and # comments.
+func readVideoIDs(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open video ID file: %w", err)
+	}
+	defer file.Close()
+
+	var videoIDs []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		videoIDs = append(videoIDs, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read video ID file: %w", err)
+	}
+	return videoIDs, nil
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_models"
+)
+
+// CaptionFormat is the format caption tracks are downloaded in, the fmt parameter of their URL.
+type CaptionFormat string
+
+const (
+	// CaptionFormatXML is the legacy <transcript><text> format, it has no word timing
+	CaptionFormatXML CaptionFormat = "xml"
+	// CaptionFormatSrv3 is the <timedtext> format, with word timing for auto-generated tracks
+	CaptionFormatSrv3 CaptionFormat = "srv3"
+	// CaptionFormatJSON3 is the JSON format, with word timing for auto-generated tracks
+	CaptionFormatJSON3 CaptionFormat = "json3"
+)
+
+// detectCaptionFormat tells the caption formats apart by their first element.
+func detectCaptionFormat(data []byte) CaptionFormat {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return CaptionFormatJSON3
+	case bytes.Contains(trimmed[:min(len(trimmed), 512)], []byte("<timedtext")):
+		return CaptionFormatSrv3
+	default:
+		return CaptionFormatXML
+	}
+}
+
+// captionSegment is a piece of a caption line, a single word in auto-generated tracks.
+// offset is in milliseconds from the start of the line.
+type captionSegment struct {
+	text   string
+	offset int64
+}
+
+// captionLine builds a transcript line from its start and duration in milliseconds and its
+// segments. Words are only set when the line was split into several timed segments.
+func (p *transcriptParser) captionLine(start, duration int64, segments []captionSegment, timedWords bool) (yt_transcript_models.TranscriptLine, bool) {
+	var raw strings.Builder
+	for _, segment := range segments {
+		raw.WriteString(segment.text)
+	}
+
+	text, spans := p.lineText(raw.String())
+	line := yt_transcript_models.TranscriptLine{
+		Text:     strings.TrimSpace(text),
+		Start:    float64(start) / 1000,
+		Duration: float64(duration) / 1000,
+		Spans:    spans,
+	}
+	// Auto-generated tracks have lines holding only a line break
+	if line.Text == "" {
+		return line, false
+	}
+
+	if !timedWords {
+		return line, true
+	}
+
+	line.Words = make([]yt_transcript_models.Word, 0, len(segments))
+	for i, segment := range segments {
+		wordText := strings.TrimSpace(cleanText(segment.text))
+		if wordText == "" {
+			continue
+		}
+
+		// A word lasts until the next one starts, the last one until the line ends
+		end := duration
+		if i+1 < len(segments) {
+			end = segments[i+1].offset
+		}
+		line.Words = append(line.Words, yt_transcript_models.Word{
+			Text:     wordText,
+			Start:    float64(start+segment.offset) / 1000,
+			Duration: float64(max(end-segment.offset, 0)) / 1000,
+		})
+	}
+	return line, true
+}
+
+func cleanText(text string) string {
+	return html.UnescapeString(cleanHTML(text, false))
+}
+
+// parseJSON3 parses {"events": [{"tStartMs", "dDurationMs", "segs": [{"utf8", "tOffsetMs"}]}]}.
+// Events without segments only define caption windows and are skipped.
+func (p *transcriptParser) parseJSON3(r io.Reader) ([]yt_transcript_models.TranscriptLine, error) {
+	type json3Document struct {
+		Events []struct {
+			Start    int64 `json:"tStartMs"`
+			Duration int64 `json:"dDurationMs"`
+			Segments []struct {
+				Text       string   `json:"utf8"`
+				Offset     int64    `json:"tOffsetMs"`
+				Confidence *float64 `json:"acAsrConf"`
+			} `json:"segs"`
+		} `json:"events"`
+	}
+
+	var document json3Document
+	if err := json.NewDecoder(r).Decode(&document); err != nil {
+		return nil, err
+	}
+
+	results := make([]yt_transcript_models.TranscriptLine, 0, len(document.Events))
+	for _, event := range document.Events {
+		if len(event.Segments) == 0 {
+			continue
+		}
+
+		// Only auto-generated tracks are timed word by word, they carry a recognition confidence
+		timedWords := len(event.Segments) > 1
+		segments := make([]captionSegment, 0, len(event.Segments))
+		for _, segment := range event.Segments {
+			segments = append(segments, captionSegment{text: segment.Text, offset: segment.Offset})
+			timedWords = timedWords || segment.Confidence != nil
+		}
+
+		if line, ok := p.captionLine(event.Start, event.Duration, segments, timedWords); ok {
+			results = append(results, line)
+		}
+	}
+	return results, nil
+}
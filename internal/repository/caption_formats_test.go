@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_models"
+)
+
+const srv3Generated = `<?xml version="1.0" encoding="utf-8" ?><timedtext format="3">
+<head><ws id="0"/></head>
+<body>
+<w t="0" id="1" wp="1" ws="1"/>
+<p t="1360" d="3640" w="1"><s ac="0">we&amp;#39;re</s><s t="480" ac="0"> no</s><s t="960" ac="0"> strangers</s></p>
+<p t="2810" d="2190" w="1" a="1">
+</p>
+<p t="5000" d="2000" w="1"><s ac="0">to</s><s t="600" ac="0"> love</s></p>
+</body>
+</timedtext>`
+
+const srv3Manual = `<?xml version="1.0" encoding="utf-8" ?><timedtext format="3">
+<body>
+<p t="0" d="1500">Hello &amp;amp; welcome</p>
+<p t="1500" d="2500">to the show</p>
+</body>
+</timedtext>`
+
+const json3Generated = `{
+  "wireMagic": "pb3",
+  "events": [
+    {"tStartMs": 0, "dDurationMs": 7000, "id": 1, "wpWinPosId": 1, "wsWinStyleId": 1},
+    {"tStartMs": 1360, "dDurationMs": 3640, "wWinId": 1, "segs": [
+      {"utf8": "we're", "acAsrConf": 0},
+      {"utf8": " no", "tOffsetMs": 480, "acAsrConf": 0},
+      {"utf8": " strangers", "tOffsetMs": 960, "acAsrConf": 0}
+    ]},
+    {"tStartMs": 2810, "dDurationMs": 2190, "wWinId": 1, "aAppend": 1, "segs": [{"utf8": "\n"}]},
+    {"tStartMs": 5000, "dDurationMs": 2000, "wWinId": 1, "segs": [{"utf8": "to", "acAsrConf": 0}]}
+  ]
+}`
+
+const json3Manual = `{"events": [{"tStartMs": 0, "dDurationMs": 1500, "segs": [{"utf8": "Hello & welcome"}]}]}`
+
+func TestParseCaptionFormats(t *testing.T) {
+	generatedFirstLine := yt_transcript_models.TranscriptLine{
+		Text:     "we're no strangers",
+		Start:    1.36,
+		Duration: 3.64,
+		Words: []yt_transcript_models.Word{
+			{Text: "we're", Start: 1.36, Duration: 0.48},
+			{Text: "no", Start: 1.84, Duration: 0.48},
+			{Text: "strangers", Start: 2.32, Duration: 2.68},
+		},
+	}
+
+	t.Run("srv3 auto-generated", func(t *testing.T) {
+		lines, err := NewTranscriptParser(false).Parse(srv3Generated)
+
+		assert.NoError(t, err)
+		assert.Len(t, lines, 2)
+		assert.Equal(t, generatedFirstLine, lines[0])
+		assert.Equal(t, []yt_transcript_models.Word{
+			{Text: "to", Start: 5, Duration: 0.6},
+			{Text: "love", Start: 5.6, Duration: 1.4},
+		}, lines[1].Words)
+	})
+
+	t.Run("srv3 manual", func(t *testing.T) {
+		lines, err := NewTranscriptParser(false).Parse(srv3Manual)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []yt_transcript_models.TranscriptLine{
+			{Text: "Hello & welcome", Start: 0, Duration: 1.5},
+			{Text: "to the show", Start: 1.5, Duration: 2.5},
+		}, lines)
+	})
+
+	t.Run("json3 auto-generated", func(t *testing.T) {
+		lines, err := NewTranscriptParser(false).Parse(json3Generated)
+
+		assert.NoError(t, err)
+		assert.Len(t, lines, 2)
+		assert.Equal(t, generatedFirstLine, lines[0])
+		assert.Equal(t, []yt_transcript_models.Word{{Text: "to", Start: 5, Duration: 2}}, lines[1].Words)
+	})
+
+	t.Run("json3 manual", func(t *testing.T) {
+		lines, err := NewTranscriptParser(false).Parse(json3Manual)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []yt_transcript_models.TranscriptLine{{Text: "Hello & welcome", Start: 0, Duration: 1.5}}, lines)
+	})
+
+	t.Run("Legacy XML", func(t *testing.T) {
+		lines, err := NewTranscriptParser(false).Parse(`<transcript><text start="1.5" dur="2">Hi</text></transcript>`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []yt_transcript_models.TranscriptLine{{Text: "Hi", Start: 1.5, Duration: 2}}, lines)
+	})
+
+	t.Run("Invalid json3", func(t *testing.T) {
+		_, err := NewTranscriptParser(false).Parse(`{"events": [`)
+		assert.Error(t, err)
+	})
+}
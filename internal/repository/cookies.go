@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cookies written with the HttpOnly flag are prefixed with this marker in cookies.txt files
+const httpOnlyPrefix = "#HttpOnly_"
+
+const youtubeOrigin = "https://www.youtube.com"
+
+// LoadCookieJar reads a Netscape-format cookies.txt file, as exported by browser
+// extensions or yt-dlp, into a cookie jar.
+func LoadCookieJar(path string) (http.CookieJar, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cookies file: %w", err)
+	}
+	defer file.Close()
+
+	return ParseCookieJar(file)
+}
+
+// ParseCookieJar reads Netscape-format cookies from r into a cookie jar.
+func ParseCookieJar(r io.Reader) (http.CookieJar, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		// Only line endings are trimmed, the value of the last field may be empty
+		line := strings.TrimRight(scanner.Text(), "\r\n")
+
+		httpOnly := strings.HasPrefix(line, httpOnlyPrefix)
+		line = strings.TrimPrefix(line, httpOnlyPrefix)
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		cookieURL, cookie, err := parseCookieLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("cookies file line %d: %w", lineNumber, err)
+		}
+		cookie.HttpOnly = httpOnly
+
+		jar.SetCookies(cookieURL, []*http.Cookie{cookie})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read cookies file: %w", err)
+	}
+
+	return jar, nil
+}
+
+// parseCookieLine parses the tab separated fields domain, include subdomains, path,
+// secure, expiry, name and value, returning the cookie and the URL it was set for.
+func parseCookieLine(line string) (*url.URL, *http.Cookie, error) {
+	fields := strings.Split(line, "\t")
+	if len(fields) != 7 {
+		return nil, nil, fmt.Errorf("expected 7 tab separated fields, got %d", len(fields))
+	}
+
+	domain, includeSubdomains, path, secure, expiry, name, value :=
+		fields[0], fields[1] == "TRUE", fields[2], fields[3] == "TRUE", fields[4], fields[5], fields[6]
+
+	expires, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid expiry %q: %w", expiry, err)
+	}
+
+	cookie := &http.Cookie{
+		Name:   name,
+		Value:  value,
+		Path:   path,
+		Secure: secure,
+	}
+	// Zero marks a session cookie
+	if expires > 0 {
+		cookie.Expires = time.Unix(expires, 0)
+	}
+
+	host := strings.TrimPrefix(domain, ".")
+	if includeSubdomains {
+		cookie.Domain = host
+	}
+
+	scheme := "http"
+	if secure {
+		scheme = "https"
+	}
+	return &url.URL{Scheme: scheme, Host: host, Path: path}, cookie, nil
+}
+
+// sapisidHash computes the Authorization header innertube at origin expects from signed
+// in sessions, derived from the SAPISID cookie of the jar. It is empty when the jar
+// holds no such cookie.
+func sapisidHash(jar http.CookieJar, origin string, now time.Time) string {
+	u, err := url.Parse(origin)
+	if err != nil {
+		return ""
+	}
+
+	for _, cookie := range jar.Cookies(u) {
+		if cookie.Name != "SAPISID" && cookie.Name != "__Secure-3PAPISID" {
+			continue
+		}
+
+		timestamp := strconv.FormatInt(now.Unix(), 10)
+		sum := sha1.Sum([]byte(timestamp + " " + cookie.Value + " " + origin))
+		return "SAPISIDHASH " + timestamp + "_" + hex.EncodeToString(sum[:])
+	}
+	return ""
+}
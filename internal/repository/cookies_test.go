@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const cookiesFile = `# Netscape HTTP Cookie File
+# This is a generated file! Do not edit.
+
+.youtube.com	TRUE	/	TRUE	4102444800	SAPISID	sapisid-value
+#HttpOnly_.youtube.com	TRUE	/	TRUE	0	LOGIN_INFO	login-value
+www.youtube.com	FALSE	/	FALSE	4102444800	PREF	hl=en
+.youtube.com	TRUE	/	FALSE	946684800	EXPIRED	old
+`
+
+func TestParseCookieJar(t *testing.T) {
+	jar, err := ParseCookieJar(strings.NewReader(cookiesFile))
+	assert.NoError(t, err)
+
+	cookieNames := func(rawURL string) []string {
+		u, _ := url.Parse(rawURL)
+		var names []string
+		for _, cookie := range jar.Cookies(u) {
+			names = append(names, cookie.Name)
+		}
+		return names
+	}
+
+	assert.ElementsMatch(t, []string{"SAPISID", "LOGIN_INFO", "PREF"}, cookieNames("https://www.youtube.com/watch?v=abc"))
+	// Host-only and secure cookies stay where they were set
+	assert.Empty(t, cookieNames("http://music.youtube.com/"))
+	assert.ElementsMatch(t, []string{"SAPISID", "LOGIN_INFO"}, cookieNames("https://music.youtube.com/"))
+}
+
+func TestParseCookieJarEmptyValue(t *testing.T) {
+	jar, err := ParseCookieJar(strings.NewReader(".youtube.com\tTRUE\t/\tTRUE\t4102444800\tEMPTY\t\r\n"))
+	assert.NoError(t, err)
+
+	u, _ := url.Parse("https://www.youtube.com/")
+	cookies := jar.Cookies(u)
+	if assert.Len(t, cookies, 1) {
+		assert.Equal(t, "EMPTY", cookies[0].Name)
+		assert.Empty(t, cookies[0].Value)
+	}
+}
+
+func TestParseCookieJarInvalidLine(t *testing.T) {
+	_, err := ParseCookieJar(strings.NewReader("# comment\n.youtube.com\tTRUE\t/\n"))
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func TestSapisidHash(t *testing.T) {
+	jar, err := ParseCookieJar(strings.NewReader(cookiesFile))
+	assert.NoError(t, err)
+
+	hash := sapisidHash(jar, youtubeOrigin, time.Unix(1700000000, 0))
+	// sha1("1700000000 sapisid-value https://www.youtube.com")
+	assert.Equal(t, "SAPISIDHASH 1700000000_ade2239a1ec948ec8b27fe884ba5c1cdc9388057", hash)
+
+	empty, _ := ParseCookieJar(strings.NewReader(""))
+	assert.Empty(t, sapisidHash(empty, youtubeOrigin, time.Now()))
+}
+
+func TestFetcherSignsWithBaseURLOrigin(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	jar, err := ParseCookieJar(strings.NewReader("127.0.0.1\tFALSE\t/\tFALSE\t0\tSAPISID\tsapisid-value\n"))
+	assert.NoError(t, err)
+
+	fetcher := NewHTMLFetcher(WithCookieJar(jar), WithBaseURL(server.URL+"/"))
+	_, err = fetcher.FetchInnertubeEndpoint(context.Background(), "browse", nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, server.URL, header.Get("X-Origin"))
+	timestamp, _, _ := strings.Cut(strings.TrimPrefix(header.Get("Authorization"), "SAPISIDHASH "), "_")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	assert.NoError(t, err)
+	assert.Equal(t, sapisidHash(jar, server.URL, time.Unix(seconds, 0)), header.Get("Authorization"))
+}
+
+func TestFetcherSendsJarCookies(t *testing.T) {
+	var received []*http.Cookie
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Cookies()
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	jar, err := ParseCookieJar(strings.NewReader("127.0.0.1\tFALSE\t/\tFALSE\t0\tSID\tsession\n"))
+	assert.NoError(t, err)
+
+	fetcher := NewHTMLFetcher(WithCookieJar(jar))
+	_, err = fetcher.FetchWithContext(context.Background(), server.URL, &http.Cookie{Name: "CONSENT", Value: "YES+"})
+
+	assert.NoError(t, err)
+	assert.Len(t, received, 2)
+	assert.Nil(t, sharedHTTPClient.Jar, "the shared client must not be modified")
+}
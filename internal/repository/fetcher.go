@@ -6,99 +6,213 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"regexp"
+	"strconv"
 	"time"
-)
-
-var video_base_url = "https://www.youtube.com/watch?v=%s"
 
-const INNERTUBE_API_URL = "https://www.youtube.com/youtubei/v1/player?key=%s"
+	yt_errors "github.com/horiagug/youtube-transcript-api-go/pkg/errors"
+)
 
-var INNERTUBE_CONTEXT = map[string]interface{}{
-	"client": map[string]interface{}{
-		"clientName":    "ANDROID",
-		"clientVersion": "20.10.38",
-	},
-}
+// Paths of the requests made to the base URL, https://www.youtube.com unless overridden
+const (
+	VIDEO_PATH         = "/watch?v=%s"
+	INNERTUBE_API_PATH = "/youtubei/v1/player?key=%s"
+	// INNERTUBE_ENDPOINT_PATH is used for every other innertube endpoint, e.g. "browse".
+	INNERTUBE_ENDPOINT_PATH = "/youtubei/v1/%s"
+)
 
 type HTMLFetcherType interface {
 	Fetch(url string, cookie *http.Cookie) ([]byte, error)
 	FetchVideo(videoID string) ([]byte, error)
 	FetchInnertubeData(ctx context.Context, videoID string, apiKey string, cookie *http.Cookie) (map[string]interface{}, error)
 	FetchWithContext(ctx context.Context, url string, cookie *http.Cookie) ([]byte, error)
+	FetchInnertubeEndpoint(ctx context.Context, endpoint string, payload map[string]interface{}) (map[string]interface{}, error)
 }
 
-// Shared HTTP client with optimized connection pooling
-var sharedHTTPClient = &http.Client{
-	Timeout: 30 * time.Second,
-	Transport: &http.Transport{
-		MaxIdleConns:        100,
-		MaxIdleConnsPerHost: 10,
-		IdleConnTimeout:     90 * time.Second,
-		DisableKeepAlives:   false,
-	},
+// StreamingFetcher is implemented by fetchers that can hand a response body to consume while
+// it is being read. consume is called again from the start when the body is retried.
+type StreamingFetcher interface {
+	FetchStreamWithContext(ctx context.Context, url string, cookie *http.Cookie, consume func(io.Reader) error) error
 }
 
-type HTMLFetcher struct{}
+const (
+	httpTimeout           = 30 * time.Second
+	defaultAcceptLanguage = "en-US"
+)
 
-func NewHTMLFetcher() *HTMLFetcher {
-	return &HTMLFetcher{}
+// Shared transport with optimized connection pooling
+var sharedTransport = &http.Transport{
+	MaxIdleConns:        100,
+	MaxIdleConnsPerHost: 10,
+	IdleConnTimeout:     90 * time.Second,
+	DisableKeepAlives:   false,
 }
 
-func (f *HTMLFetcher) Fetch(url string, cookie *http.Cookie) ([]byte, error) {
-	return f.FetchWithContext(context.Background(), url, cookie)
+// Shared HTTP client used by every fetcher without a proxy
+var sharedHTTPClient = &http.Client{
+	Timeout:   httpTimeout,
+	Transport: sharedTransport,
 }
 
-func (f *HTMLFetcher) FetchWithContext(ctx context.Context, url string, cookie *http.Cookie) ([]byte, error) {
-	var body []byte
-	var err error
+type HTMLFetcher struct {
+	client         *http.Client
+	streamClient   *http.Client
+	baseURL        string
+	retryPolicy    RetryPolicy
+	rateLimiter    *RateLimiter
+	logger         *slog.Logger
+	httpClient     *http.Client
+	roundTripper   http.RoundTripper
+	proxyURL       *url.URL
+	proxyPool      *ProxyPool
+	cookieJar      http.CookieJar
+	clientProfiles []ClientProfile
+	userAgent      string
+	acceptLanguage string
+	headers        http.Header
+}
 
-	for i := range 3 {
-		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
-		}
+func NewHTMLFetcher(options ...FetcherOption) *HTMLFetcher {
+	f := &HTMLFetcher{
+		baseURL:        youtubeOrigin,
+		acceptLanguage: defaultAcceptLanguage,
+		retryPolicy:    DefaultRetryPolicy(),
+		logger:         DiscardLogger,
+		clientProfiles: DefaultClientProfiles(),
+	}
 
-		req.Header.Set("Accept-Language", "en-US")
-		if cookie != nil {
-			req.AddCookie(cookie)
+	for _, opt := range options {
+		opt(f)
+	}
+
+	f.client = f.buildClient()
+	if f.cookieJar != nil {
+		// Copied so that the shared or caller's client is left untouched
+		client := *f.client
+		client.Jar = f.cookieJar
+		f.client = &client
+	}
+
+	// Streamed bodies are read at the pace of their consumer, see streamWithRetry
+	streamClient := *f.client
+	streamClient.Timeout = 0
+	f.streamClient = &streamClient
+	return f
+}
+
+// buildClient picks the HTTP client: a custom client wins over a custom round tripper,
+// which wins over the proxy options configuring the default transport.
+func (f *HTMLFetcher) buildClient() *http.Client {
+	switch {
+	case f.httpClient != nil:
+		return f.httpClient
+	case f.roundTripper != nil:
+		return &http.Client{
+			Timeout:   httpTimeout,
+			Transport: f.roundTripper,
+		}
+	case f.proxyURL != nil || f.proxyPool != nil:
+		// Proxied connections can't be shared with direct ones
+		transport := sharedTransport.Clone()
+		var roundTripper http.RoundTripper = transport
+
+		if f.proxyPool != nil {
+			roundTripper = f.proxyPool.wrap(transport)
+		} else {
+			transport.Proxy = http.ProxyURL(f.proxyURL)
 		}
 
-		resp, err := sharedHTTPClient.Do(req)
-		if err != nil {
-			fmt.Printf("Retry %d: failed to fetch: %v\n", i+1, err)
-			time.Sleep(2 * time.Second) // Wait before retrying
-			continue
+		return &http.Client{
+			Timeout:   httpTimeout,
+			Transport: roundTripper,
 		}
-		defer resp.Body.Close()
+	default:
+		return sharedHTTPClient
+	}
+}
+
+// newRequest creates a request carrying the configured default headers.
+func (f *HTMLFetcher) newRequest(ctx context.Context, method string, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
 
-		if resp.StatusCode != http.StatusOK {
-			fmt.Printf("Retry %d: received non-OK status code: %d\n", i+1, resp.StatusCode)
-			time.Sleep(2 * time.Second)
-			continue
+	for name, values := range f.headers {
+		for _, value := range values {
+			req.Header.Add(name, value)
 		}
+	}
+	if f.acceptLanguage != "" {
+		req.Header.Set("Accept-Language", f.acceptLanguage)
+	}
+	if f.userAgent != "" {
+		req.Header.Set("User-Agent", f.userAgent)
+	}
+
+	return req, nil
+}
+
+// authorize signs innertube requests of a signed in session, without it YouTube
+// ignores the account cookies of the jar.
+func (f *HTMLFetcher) authorize(req *http.Request) {
+	if f.cookieJar == nil {
+		return
+	}
+	origin := f.origin()
+	if hash := sapisidHash(f.cookieJar, origin, time.Now()); hash != "" {
+		req.Header.Set("Authorization", hash)
+		req.Header.Set("X-Origin", origin)
+	}
+}
+
+// origin is the scheme and host of the base URL requests are sent to.
+func (f *HTMLFetcher) origin() string {
+	u, err := url.Parse(f.baseURL)
+	if err != nil || u.Host == "" {
+		return youtubeOrigin
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+func (f *HTMLFetcher) Fetch(url string, cookie *http.Cookie) ([]byte, error) {
+	return f.FetchWithContext(context.Background(), url, cookie)
+}
+
+func (f *HTMLFetcher) FetchWithContext(ctx context.Context, url string, cookie *http.Cookie) ([]byte, error) {
+	return f.doWithRetry(ctx, f.getRequest(ctx, url, cookie))
+}
+
+// FetchStreamWithContext is FetchWithContext without buffering, the body is handed to consume.
+// Errors returned by consume are returned as is, only failures reading the body are retried.
+func (f *HTMLFetcher) FetchStreamWithContext(ctx context.Context, url string, cookie *http.Cookie, consume func(io.Reader) error) error {
+	return f.streamWithRetry(ctx, f.getRequest(ctx, url, cookie), consume)
+}
 
-		body, err = io.ReadAll(resp.Body)
+func (f *HTMLFetcher) getRequest(ctx context.Context, url string, cookie *http.Cookie) func() (*http.Request, error) {
+	return func() (*http.Request, error) {
+		req, err := f.newRequest(ctx, "GET", url, nil)
 		if err != nil {
-			fmt.Printf("Retry %d: failed to read response body: %v\n", i+1, err)
-			time.Sleep(2 * time.Second)
-			continue
+			return nil, err
 		}
 
-		if len(body) > 0 {
-			return body, nil // Success
+		if cookie != nil {
+			req.AddCookie(cookie)
 		}
-
-		fmt.Printf("Retry %d: empty response body\n", i+1)
-		time.Sleep(2 * time.Second)
+		return req, nil
 	}
+}
 
-	return nil, fmt.Errorf("failed to fetch after retries: %w", err)
+// videoURL is the watch page URL of videoID.
+func (f *HTMLFetcher) videoURL(videoID string) string {
+	return f.baseURL + fmt.Sprintf(VIDEO_PATH, url.QueryEscape(videoID))
 }
 
 func (f *HTMLFetcher) FetchVideo(videoID string) ([]byte, error) {
-	video_url := fmt.Sprintf(video_base_url, videoID)
+	video_url := f.videoURL(videoID)
 
 	body, err := f.Fetch(video_url, nil)
 	if err != nil {
@@ -106,10 +220,10 @@ func (f *HTMLFetcher) FetchVideo(videoID string) ([]byte, error) {
 	}
 
 	if consentRequired(body) {
-		fmt.Println("Consent required, attempting to set cookie and retry")
+		f.logger.Info("consent required, retrying with consent cookie", "video_id", videoID, "url", video_url)
 		cookie, err := f.createConsentCookie(video_url)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create consent cookie: %w", err)
+			return nil, &yt_errors.ConsentError{Err: err}
 		}
 
 		body, err = f.Fetch(video_url, cookie) // Retry fetch with cookie
@@ -121,8 +235,10 @@ func (f *HTMLFetcher) FetchVideo(videoID string) ([]byte, error) {
 	return body, nil
 }
 
-func (f *HTMLFetcher) createConsentCookie(videoID string) (*http.Cookie, error) {
-	html, err := f.Fetch(videoID, nil)
+// createConsentCookie accepts the cookie consent with the value of the consent form served
+// instead of videoURL.
+func (f *HTMLFetcher) createConsentCookie(videoURL string) (*http.Cookie, error) {
+	html, err := f.Fetch(videoURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch HTML to extract consent value: %w", err)
 	}
@@ -148,63 +264,63 @@ func consentRequired(body []byte) bool {
 	return consentRegex.Match(body)
 }
 
-func (f *HTMLFetcher) FetchInnertubeData(ctx context.Context, videoID string, apiKey string, cookie *http.Cookie) (map[string]interface{}, error) {
+// FetchInnertubeEndpoint posts payload to an innertube endpoint such as "browse" or
+// "navigation/resolve_url" using the WEB client profile, the only one these endpoints answer.
+func (f *HTMLFetcher) FetchInnertubeEndpoint(ctx context.Context, endpoint string, payload map[string]interface{}) (map[string]interface{}, error) {
+	url := f.baseURL + fmt.Sprintf(INNERTUBE_ENDPOINT_PATH, endpoint)
 
-	url := fmt.Sprintf(INNERTUBE_API_URL, apiKey)
-
-	payload := map[string]interface{}{
-		"context": INNERTUBE_CONTEXT,
-		"videoId": videoID,
+	body := make(map[string]interface{}, len(payload)+1)
+	for key, value := range payload {
+		body[key] = value
 	}
-	payloadBytes, err := json.Marshal(payload)
+	body["context"] = WebProfile.context()
+
+	payloadBytes, err := json.Marshal(body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal JSON payload: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(payloadBytes))
+	responseBody, err := f.doWithRetry(ctx, func() (*http.Request, error) {
+		req, err := f.newRequest(ctx, "POST", url, bytes.NewReader(payloadBytes))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		WebProfile.setHeaders(req)
+		f.authorize(req)
+		return req, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+		return nil, err
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	if cookie != nil {
-		req.AddCookie(cookie)
+	var responseData map[string]interface{}
+	if err := json.Unmarshal(responseBody, &responseData); err != nil {
+		return nil, &yt_errors.ParseError{What: "innertube " + endpoint + " response", Err: err}
 	}
 
-	resp, err := sharedHTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute HTTP request: %w", err)
-	}
-	defer resp.Body.Close()
+	return responseData, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("received non-OK status code: %d", resp.StatusCode)
+func tooManyRequests(url string, resp *http.Response) error {
+	return &yt_errors.TooManyRequestsError{
+		URL:        url,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
 	}
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+// parseRetryAfter understands both forms allowed for Retry-After: delay seconds and an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
 	}
-
-	if consentRequired(body) && cookie == nil {
-
-		cookie, err := f.createConsentCookie(videoID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create consent cookie: %w", err)
-		}
-
-		responseData, err := f.FetchInnertubeData(ctx, videoID, apiKey, cookie)
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch video page after setting consent: %w", err)
-		}
-		return responseData, nil
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
 	}
-
-	var responseData map[string]interface{}
-	err = json.Unmarshal(body, &responseData)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decode response JSON: %w", err)
+	if date, err := http.ParseTime(value); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d
+		}
 	}
-
-	return responseData, nil
+	return 0
 }
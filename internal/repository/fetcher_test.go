@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingRoundTripper struct {
+	calls int
+	base  http.RoundTripper
+}
+
+func (c *countingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.calls++
+	return c.base.RoundTrip(req)
+}
+
+func TestFetcherSendsConfiguredHeaders(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	fetcher := NewHTMLFetcher(
+		WithUserAgent("test-agent/1.0"),
+		WithAcceptLanguage("de-DE"),
+		WithHeaders(http.Header{"X-Request-Id": []string{"abc"}}),
+	)
+
+	body, err := fetcher.FetchWithContext(context.Background(), server.URL, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", string(body))
+	assert.Equal(t, "test-agent/1.0", received.Get("User-Agent"))
+	assert.Equal(t, "de-DE", received.Get("Accept-Language"))
+	assert.Equal(t, "abc", received.Get("X-Request-Id"))
+}
+
+func TestFetcherDefaultAcceptLanguage(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	_, err := NewHTMLFetcher().FetchWithContext(context.Background(), server.URL, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "en-US", received.Get("Accept-Language"))
+}
+
+func TestFetcherUsesCustomTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	t.Run("Round tripper", func(t *testing.T) {
+		roundTripper := &countingRoundTripper{base: http.DefaultTransport}
+		fetcher := NewHTMLFetcher(WithRoundTripper(roundTripper))
+
+		_, err := fetcher.FetchWithContext(context.Background(), server.URL, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, roundTripper.calls)
+	})
+
+	t.Run("HTTP client", func(t *testing.T) {
+		roundTripper := &countingRoundTripper{base: http.DefaultTransport}
+		fetcher := NewHTMLFetcher(WithHTTPClient(&http.Client{Transport: roundTripper}))
+
+		_, err := fetcher.FetchWithContext(context.Background(), server.URL, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, roundTripper.calls)
+	})
+}
@@ -31,3 +31,8 @@ func (m *MockHTMLFetcher) FetchWithContext(ctx context.Context, url string, cook
 	args := m.Called(ctx, url, cookie)
 	return args.Get(0).([]byte), args.Error(1)
 }
+
+func (m *MockHTMLFetcher) FetchInnertubeEndpoint(ctx context.Context, endpoint string, payload map[string]interface{}) (map[string]interface{}, error) {
+	args := m.Called(ctx, endpoint, payload)
+	return args.Get(0).(map[string]interface{}), args.Error(1)
+}
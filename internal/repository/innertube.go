@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	yt_errors "github.com/horiagug/youtube-transcript-api-go/pkg/errors"
+)
+
+// ClientProfile is the innertube client a player request impersonates. YouTube
+// regularly stops serving captions to outdated clients, so several profiles are
+// tried in order, see DefaultClientProfiles.
+type ClientProfile struct {
+	// Name is the innertube clientName, e.g. "ANDROID"
+	Name string
+	// ID is the numeric client name sent in the X-YouTube-Client-Name header
+	ID        int
+	Version   string
+	UserAgent string
+	// Context holds additional fields of the client context, e.g. the device model
+	Context map[string]interface{}
+	// Embedded clients play videos as if embedded in a third party page
+	Embedded bool
+}
+
+var (
+	AndroidProfile = ClientProfile{
+		Name:      "ANDROID",
+		ID:        3,
+		Version:   "20.10.38",
+		UserAgent: "com.google.android.youtube/20.10.38 (Linux; U; Android 14) gzip",
+		Context: map[string]interface{}{
+			"androidSdkVersion": 34,
+			"osName":            "Android",
+			"osVersion":         "14",
+		},
+	}
+
+	IOSProfile = ClientProfile{
+		Name:      "IOS",
+		ID:        5,
+		Version:   "20.10.4",
+		UserAgent: "com.google.ios.youtube/20.10.4 (iPhone16,2; U; CPU iOS 18_3_2 like Mac OS X;)",
+		Context: map[string]interface{}{
+			"deviceMake":  "Apple",
+			"deviceModel": "iPhone16,2",
+			"osName":      "iPhone",
+			"osVersion":   "18.3.2.22D82",
+		},
+	}
+
+	WebProfile = ClientProfile{
+		Name:      "WEB",
+		ID:        1,
+		Version:   "2.20250312.04.00",
+		UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/134.0.0.0 Safari/537.36",
+		Context: map[string]interface{}{
+			"hl": "en",
+		},
+	}
+
+	TVEmbeddedProfile = ClientProfile{
+		Name:      "TVHTML5_SIMPLY_EMBEDDED_PLAYER",
+		ID:        85,
+		Version:   "2.0",
+		UserAgent: "Mozilla/5.0 (ChromiumStylePlatform) Cobalt/Version",
+		Embedded:  true,
+	}
+)
+
+// DefaultClientProfiles returns the profiles tried by default, in order.
+func DefaultClientProfiles() []ClientProfile {
+	return []ClientProfile{AndroidProfile, IOSProfile, WebProfile, TVEmbeddedProfile}
+}
+
+// context builds the innertube request context of the profile.
+func (p ClientProfile) context() map[string]interface{} {
+	client := make(map[string]interface{}, len(p.Context)+2)
+	for key, value := range p.Context {
+		client[key] = value
+	}
+	client["clientName"] = p.Name
+	client["clientVersion"] = p.Version
+
+	context := map[string]interface{}{"client": client}
+	if p.Embedded {
+		context["thirdParty"] = map[string]interface{}{"embedUrl": "https://www.youtube.com/"}
+	}
+	return context
+}
+
+// setHeaders identifies the request as coming from the profile's client.
+func (p ClientProfile) setHeaders(req *http.Request) {
+	if p.UserAgent != "" {
+		req.Header.Set("User-Agent", p.UserAgent)
+	}
+	if p.ID != 0 {
+		req.Header.Set("X-YouTube-Client-Name", strconv.Itoa(p.ID))
+	}
+	req.Header.Set("X-YouTube-Client-Version", p.Version)
+}
+
+// FetchInnertubeData fetches the player response of videoID, trying the client profiles in
+// order until one returns a playable video with captions. When none does, the first response
+// of a playable video is returned, or the first response at all, so that callers can tell
+// why the captions are missing. A failing request ends the chain, it was retried already.
+func (f *HTMLFetcher) FetchInnertubeData(ctx context.Context, videoID string, apiKey string, cookie *http.Cookie) (map[string]interface{}, error) {
+	var fallback map[string]interface{}
+	var fallbackPlayable bool
+
+	for _, profile := range f.clientProfiles {
+		responseData, err := f.fetchPlayer(ctx, profile, videoID, apiKey, cookie)
+		if err != nil {
+			// Other clients would repeat every retry, e.g. when rate limited or cancelled
+			return nil, err
+		}
+
+		playable, status := playabilityStatus(responseData)
+		if playable && hasCaptions(responseData) {
+			return responseData, nil
+		}
+		f.logger.Debug("innertube client returned no captions, trying next", "video_id", videoID, "client", profile.Name, "status", status)
+
+		if fallback == nil || (playable && !fallbackPlayable) {
+			fallback, fallbackPlayable = responseData, playable
+		}
+	}
+
+	if fallback != nil {
+		return fallback, nil
+	}
+	return nil, fmt.Errorf("no innertube client profiles configured")
+}
+
+// fetchPlayer sends the player request of a single client profile.
+func (f *HTMLFetcher) fetchPlayer(ctx context.Context, profile ClientProfile, videoID string, apiKey string, cookie *http.Cookie) (map[string]interface{}, error) {
+	url := f.baseURL + fmt.Sprintf(INNERTUBE_API_PATH, apiKey)
+
+	payload := map[string]interface{}{
+		"context": profile.context(),
+		"videoId": videoID,
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON payload: %w", err)
+	}
+
+	body, err := f.doWithRetry(ctx, func() (*http.Request, error) {
+		req, err := f.newRequest(ctx, "POST", url, bytes.NewReader(payloadBytes))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		profile.setHeaders(req)
+		f.authorize(req)
+
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		return req, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if consentRequired(body) && cookie == nil {
+		f.logger.Info("consent required, retrying innertube request with consent cookie", "video_id", videoID, "client", profile.Name)
+
+		cookie, err := f.createConsentCookie(f.videoURL(videoID))
+		if err != nil {
+			return nil, &yt_errors.ConsentError{Err: err}
+		}
+
+		responseData, err := f.fetchPlayer(ctx, profile, videoID, apiKey, cookie)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch video page after setting consent: %w", err)
+		}
+		return responseData, nil
+	}
+
+	var responseData map[string]interface{}
+	if err := json.Unmarshal(body, &responseData); err != nil {
+		return nil, &yt_errors.ParseError{What: "innertube response", Err: err}
+	}
+
+	return responseData, nil
+}
+
+// playabilityStatus reports whether the player response is of a playable video, and its status.
+func playabilityStatus(data map[string]interface{}) (bool, string) {
+	playability, ok := data["playabilityStatus"].(map[string]interface{})
+	if !ok {
+		return true, ""
+	}
+	status, _ := playability["status"].(string)
+	return status == "" || status == "OK", status
+}
+
+func hasCaptions(data map[string]interface{}) bool {
+	captions, _ := data["captions"].(map[string]interface{})
+	renderer, _ := captions["playerCaptionsTracklistRenderer"].(map[string]interface{})
+	tracks, _ := renderer["captionTracks"].([]interface{})
+	return len(tracks) > 0
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	yt_errors "github.com/horiagug/youtube-transcript-api-go/pkg/errors"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// playerTransport answers player requests with the response registered for the client name.
+func playerTransport(t *testing.T, clients *[]string, responses map[string]string) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		var payload struct {
+			Context struct {
+				Client struct {
+					ClientName string `json:"clientName"`
+				} `json:"client"`
+			} `json:"context"`
+		}
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&payload))
+
+		name := payload.Context.Client.ClientName
+		*clients = append(*clients, name)
+
+		status := http.StatusOK
+		body, ok := responses[name]
+		if !ok {
+			status, body = http.StatusBadRequest, "{}"
+		}
+		return &http.Response{
+			StatusCode: status,
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Header:     http.Header{},
+			Request:    req,
+		}, nil
+	})
+}
+
+const playerWithCaptions = `{"playabilityStatus": {"status": "OK"}, "captions": {"playerCaptionsTracklistRenderer": {"captionTracks": [{"languageCode": "en"}]}}}`
+
+func TestFetchInnertubeDataFallsBackToNextProfile(t *testing.T) {
+	var clients []string
+	fetcher := NewHTMLFetcher(
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 1}),
+		WithRoundTripper(playerTransport(t, &clients, map[string]string{
+			"ANDROID": `{"playabilityStatus": {"status": "UNPLAYABLE", "reason": "This client is no longer supported"}}`,
+			"IOS":     `{"playabilityStatus": {"status": "OK"}}`,
+			"WEB":     playerWithCaptions,
+		})),
+	)
+
+	data, err := fetcher.FetchInnertubeData(context.Background(), "abc123", "key", nil)
+
+	assert.NoError(t, err)
+	assert.True(t, hasCaptions(data))
+	// IOS returns no captions and is skipped, TV embedded is never asked
+	assert.Equal(t, []string{"ANDROID", "IOS", "WEB"}, clients)
+}
+
+func TestFetchInnertubeDataPrefersPlayableResponse(t *testing.T) {
+	var clients []string
+	fetcher := NewHTMLFetcher(
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 1}),
+		WithClientProfiles(AndroidProfile, WebProfile),
+		WithRoundTripper(playerTransport(t, &clients, map[string]string{
+			"ANDROID": `{"playabilityStatus": {"status": "LOGIN_REQUIRED"}}`,
+			"WEB":     `{"playabilityStatus": {"status": "OK"}, "videoDetails": {"title": "No captions"}}`,
+		})),
+	)
+
+	data, err := fetcher.FetchInnertubeData(context.Background(), "abc123", "key", nil)
+
+	assert.NoError(t, err)
+	playable, status := playabilityStatus(data)
+	assert.True(t, playable)
+	assert.Equal(t, "OK", status)
+	assert.Equal(t, []string{"ANDROID", "WEB"}, clients)
+}
+
+func TestFetchInnertubeDataStopsOnFailedRequest(t *testing.T) {
+	var clients []string
+	fetcher := NewHTMLFetcher(
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 1}),
+		WithClientProfiles(IOSProfile, TVEmbeddedProfile),
+		WithRoundTripper(playerTransport(t, &clients, nil)),
+	)
+
+	_, err := fetcher.FetchInnertubeData(context.Background(), "abc123", "key", nil)
+
+	assert.ErrorContains(t, err, "400")
+	assert.Equal(t, []string{"IOS"}, clients)
+}
+
+func TestFetchInnertubeDataStopsWhenRateLimited(t *testing.T) {
+	requests := 0
+	fetcher := NewHTMLFetcher(
+		WithRetryPolicy(fastRetryPolicy),
+		WithRoundTripper(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			requests++
+			return &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Body:       io.NopCloser(strings.NewReader("")),
+				Header:     http.Header{},
+				Request:    req,
+			}, nil
+		})),
+	)
+
+	_, err := fetcher.FetchInnertubeData(context.Background(), "abc123", "key", nil)
+
+	assert.ErrorIs(t, err, yt_errors.ErrTooManyRequests)
+	// Only the first client is retried, the others are never asked
+	assert.Equal(t, fastRetryPolicy.MaxAttempts, requests)
+}
+
+func TestClientProfileContext(t *testing.T) {
+	context := TVEmbeddedProfile.context()
+	client := context["client"].(map[string]interface{})
+
+	assert.Equal(t, "TVHTML5_SIMPLY_EMBEDDED_PLAYER", client["clientName"])
+	assert.Equal(t, "2.0", client["clientVersion"])
+	assert.Contains(t, context, "thirdParty")
+
+	client = AndroidProfile.context()["client"].(map[string]interface{})
+	assert.Equal(t, 34, client["androidSdkVersion"])
+	assert.NotContains(t, AndroidProfile.Context, "clientName", "building the context must not modify the profile")
+}
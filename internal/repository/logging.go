@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+)
+
+// DiscardLogger drops every record, it is the default logger of fetchers and services.
+var DiscardLogger = slog.New(discardHandler{})
+
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
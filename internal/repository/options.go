@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type FetcherOption func(*HTMLFetcher)
+
+// WithProxy routes every request through a single http, https or socks5 proxy.
+func WithProxy(proxyURL *url.URL) FetcherOption {
+	return func(f *HTMLFetcher) {
+		f.proxyURL = proxyURL
+	}
+}
+
+// WithProxyPool rotates requests over the proxies of pool.
+func WithProxyPool(pool *ProxyPool) FetcherOption {
+	return func(f *HTMLFetcher) {
+		f.proxyPool = pool
+	}
+}
+
+// WithClientProfiles sets the innertube clients tried in order for player requests,
+// falling back to the next one when a client gets no captions or an unplayable video.
+func WithClientProfiles(profiles ...ClientProfile) FetcherOption {
+	return func(f *HTMLFetcher) {
+		if len(profiles) > 0 {
+			f.clientProfiles = profiles
+		}
+	}
+}
+
+// WithCookieJar sends the cookies of jar with every watch page, innertube and caption
+// request, e.g. those of a signed in account loaded with LoadCookieJar.
+func WithCookieJar(jar http.CookieJar) FetcherOption {
+	return func(f *HTMLFetcher) {
+		f.cookieJar = jar
+	}
+}
+
+// WithHTTPClient sends every request with client. Proxy and round tripper options are
+// ignored, configure them on the client instead.
+func WithHTTPClient(client *http.Client) FetcherOption {
+	return func(f *HTMLFetcher) {
+		f.httpClient = client
+	}
+}
+
+// WithRoundTripper sends every request through roundTripper, e.g. a tracing transport.
+// Proxy options are ignored, configure them on the round tripper instead.
+func WithRoundTripper(roundTripper http.RoundTripper) FetcherOption {
+	return func(f *HTMLFetcher) {
+		f.roundTripper = roundTripper
+	}
+}
+
+// WithBaseURL sends the watch page and innertube requests to baseURL instead of
+// https://www.youtube.com, e.g. to a fake server in tests.
+func WithBaseURL(baseURL string) FetcherOption {
+	return func(f *HTMLFetcher) {
+		f.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+func WithUserAgent(userAgent string) FetcherOption {
+	return func(f *HTMLFetcher) {
+		f.userAgent = userAgent
+	}
+}
+
+// WithAcceptLanguage sets the Accept-Language header, "en-US" by default.
+func WithAcceptLanguage(language string) FetcherOption {
+	return func(f *HTMLFetcher) {
+		f.acceptLanguage = language
+	}
+}
+
+// WithHeaders adds headers to every request. User-Agent and Accept-Language are
+// overridden by their dedicated options.
+func WithHeaders(headers http.Header) FetcherOption {
+	return func(f *HTMLFetcher) {
+		f.headers = headers.Clone()
+	}
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy for page, innertube and caption requests.
+func WithRetryPolicy(policy RetryPolicy) FetcherOption {
+	return func(f *HTMLFetcher) {
+		f.retryPolicy = policy
+	}
+}
+
+// WithRateLimiter throttles every page, innertube and caption request, retries included.
+func WithRateLimiter(limiter *RateLimiter) FetcherOption {
+	return func(f *HTMLFetcher) {
+		f.rateLimiter = limiter
+	}
+}
+
+// WithLogger logs retries, consent handling and rate limiting to logger. Nothing is logged by default.
+func WithLogger(logger *slog.Logger) FetcherOption {
+	return func(f *HTMLFetcher) {
+		if logger != nil {
+			f.logger = logger
+		}
+	}
+}
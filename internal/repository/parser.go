@@ -1,113 +1,88 @@
 package repository
 
 import (
-	"encoding/xml"
 	"html"
 	"regexp"
-	"strconv"
+	"slices"
 	"strings"
-	"sync"
 
 	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_models"
 )
 
 type transcriptParser struct {
-	htmlRegex *regexp.Regexp
+	preserveFormatting bool
+	spans              bool
 }
 
+type ParserOption func(*transcriptParser)
+
+// formattingTags are kept in the text when formatting is preserved
 var formattingTags = []string{
-	"strong", "em", "b", "i", "mark", "small", "del", "ins", "sub", "sup",
+	"strong", "em", "b", "i", "u", "mark", "small", "del", "ins", "sub", "sup",
 }
 
 // Pre-compiled regex patterns for better performance
 var (
-	htmlRegex     = regexp.MustCompile(`(?i)<[^>]*>`)
-	regexCache    = make(map[string]*regexp.Regexp)
-	regexCacheMu  sync.RWMutex
+	htmlRegex = regexp.MustCompile(`(?i)<[^>]*>`)
+	tagRegex  = regexp.MustCompile(`^<(/?)\s*([a-zA-Z][a-zA-Z0-9]*)`)
 )
 
-func NewTranscriptParser(preserveFormatting bool) *transcriptParser {
-	htmlRegex := getHTMLRegex(preserveFormatting)
-	return &transcriptParser{htmlRegex: htmlRegex}
+func NewTranscriptParser(preserveFormatting bool, options ...ParserOption) *transcriptParser {
+	p := &transcriptParser{preserveFormatting: preserveFormatting}
+	for _, opt := range options {
+		opt(p)
+	}
+	return p
 }
 
-func getHTMLRegex(preserveFormatting bool) *regexp.Regexp {
-	if preserveFormatting {
-		// Use cached regex or compile and cache it
-		cacheKey := "formatting_" + strings.Join(formattingTags, "|")
-		
-		regexCacheMu.RLock()
-		if regex, exists := regexCache[cacheKey]; exists {
-			regexCacheMu.RUnlock()
-			return regex
-		}
-		regexCacheMu.RUnlock()
-		
-		regexCacheMu.Lock()
-		defer regexCacheMu.Unlock()
-		
-		// Double-check after acquiring write lock
-		if regex, exists := regexCache[cacheKey]; exists {
-			return regex
-		}
-		
-		formatsRegex := `</?(?:` + strings.Join(formattingTags, "|") + `)\b[^>]*>`
-		regex := regexp.MustCompile(`(?i)<[^>]*>(?:(?i)` + formatsRegex + `)?`)
-		regexCache[cacheKey] = regex
-		return regex
+// WithSpans splits the text of every line into spans of bold, italic and underlined text,
+// whether the formatting tags are preserved in the text or not.
+func WithSpans(enabled bool) ParserOption {
+	return func(p *transcriptParser) {
+		p.spans = enabled
 	}
-	return htmlRegex
 }
 
+// cleanHTML drops markup from text. When preserving formatting, the formattingTags are kept,
+// normalized to lowercase and without attributes.
 func cleanHTML(text string, preserveFormatting bool) string {
-	cleaned := htmlRegex.ReplaceAllString(text, "")
+	if !preserveFormatting {
+		return htmlRegex.ReplaceAllString(text, "")
+	}
 
-	if preserveFormatting {
-		for _, tag := range formattingTags {
-			cleaned = strings.ReplaceAll(cleaned, "&lt;"+tag+"&gt;", "<"+tag+">")
-			cleaned = strings.ReplaceAll(cleaned, "&lt;/"+tag+"&gt;", "</"+tag+">")
+	return htmlRegex.ReplaceAllStringFunc(text, func(tag string) string {
+		match := tagRegex.FindStringSubmatch(tag)
+		if match == nil {
+			return ""
 		}
-	}
 
-	return cleaned
+		name := strings.ToLower(match[2])
+		if !slices.Contains(formattingTags, name) {
+			return ""
+		}
+		return "<" + match[1] + name + ">"
+	})
 }
 
-func (p *transcriptParser) Parse(plainData string) ([]yt_transcript_models.TranscriptLine, error) {
-	type XMLTranscript struct {
-		XMLName xml.Name `xml:"transcript"`
-		Texts   []struct {
-			Text     string `xml:",chardata"`
-			Start    string `xml:"start,attr"`
-			Duration string `xml:"dur,attr"`
-		} `xml:"text"`
+// lineText cleans the raw text of a line, splitting it into spans if enabled, and unescapes it.
+// Tags are handled before unescaping, so escaped angle brackets in the text stay text.
+func (p *transcriptParser) lineText(raw string) (string, []yt_transcript_models.Span) {
+	cleaned := cleanHTML(raw, p.preserveFormatting || p.spans)
+	if !p.spans {
+		return html.UnescapeString(cleaned), nil
 	}
 
-	var parsedXML XMLTranscript
-	err := xml.Unmarshal([]byte(plainData), &parsedXML)
-	if err != nil {
-		return nil, err
+	spans := parseSpans(cleaned)
+	for i := range spans {
+		spans[i].Text = html.UnescapeString(spans[i].Text)
 	}
-
-	var results []yt_transcript_models.TranscriptLine
-	for _, entry := range parsedXML.Texts {
-		text := cleanHTML(entry.Text, false)
-		text = html.UnescapeString(text)
-
-		start, err := strconv.ParseFloat(entry.Start, 64)
-		if err != nil {
-			start = 0.0
-		}
-
-		duration, err := strconv.ParseFloat(entry.Duration, 64)
-		if err != nil {
-			duration = 0.0
-		}
-
-		results = append(results, yt_transcript_models.TranscriptLine{
-			Text:     text,
-			Start:    start,
-			Duration: duration,
-		})
+	if !p.preserveFormatting {
+		cleaned = cleanHTML(cleaned, false)
 	}
-	return results, nil
+	return html.UnescapeString(cleaned), spans
+}
+
+// Parse parses a caption track in any of the caption formats, which is detected from the data.
+func (p *transcriptParser) Parse(plainData string) ([]yt_transcript_models.TranscriptLine, error) {
+	return p.ParseReader(strings.NewReader(plainData))
 }
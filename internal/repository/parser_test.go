@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_models"
+)
+
+// Formatting tags arrive escaped inside the caption XML
+const formattedTranscript = `<transcript>
+<text start="0" dur="2">&lt;b&gt;Hello&lt;/b&gt; &lt;font color="#fff"&gt;big&lt;/font&gt; &lt;I class="x"&gt;world&lt;/I&gt;</text>
+<text start="2" dur="1">it&amp;#39;s &lt;u&gt;&lt;em&gt;fine&lt;/em&gt;&lt;/u&gt;</text>
+</transcript>`
+
+func TestParsePreserveFormatting(t *testing.T) {
+	t.Run("Stripped", func(t *testing.T) {
+		lines, err := NewTranscriptParser(false).Parse(formattedTranscript)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Hello big world", lines[0].Text)
+		assert.Equal(t, "it's fine", lines[1].Text)
+		assert.Nil(t, lines[0].Spans)
+	})
+
+	t.Run("Preserved", func(t *testing.T) {
+		lines, err := NewTranscriptParser(true).Parse(formattedTranscript)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "<b>Hello</b> big <i>world</i>", lines[0].Text)
+		assert.Equal(t, "it's <u><em>fine</em></u>", lines[1].Text)
+	})
+
+	t.Run("Spans", func(t *testing.T) {
+		lines, err := NewTranscriptParser(false, WithSpans(true)).Parse(formattedTranscript)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Hello big world", lines[0].Text)
+		assert.Equal(t, []yt_transcript_models.Span{
+			{Text: "Hello", Bold: true},
+			{Text: " big "},
+			{Text: "world", Italic: true},
+		}, lines[0].Spans)
+		assert.Equal(t, []yt_transcript_models.Span{
+			{Text: "it's "},
+			{Text: "fine", Italic: true, Underline: true},
+		}, lines[1].Spans)
+	})
+}
+
+func TestParseEscapedAngleBrackets(t *testing.T) {
+	// Literal brackets arrive escaped twice, unlike formatting tags
+	transcript := `<transcript><text start="0" dur="2">if x &amp;lt; 3 and &lt;b&gt;y&lt;/b&gt; &amp;gt; 2 then</text></transcript>`
+
+	lines, err := NewTranscriptParser(false).Parse(transcript)
+	assert.NoError(t, err)
+	assert.Equal(t, "if x < 3 and y > 2 then", lines[0].Text)
+
+	lines, err = NewTranscriptParser(true).Parse(transcript)
+	assert.NoError(t, err)
+	assert.Equal(t, "if x < 3 and <b>y</b> > 2 then", lines[0].Text)
+
+	lines, err = NewTranscriptParser(false, WithSpans(true)).Parse(transcript)
+	assert.NoError(t, err)
+	assert.Equal(t, "if x < 3 and y > 2 then", lines[0].Text)
+	assert.Equal(t, []yt_transcript_models.Span{
+		{Text: "if x < 3 and "},
+		{Text: "y", Bold: true},
+		{Text: " > 2 then"},
+	}, lines[0].Spans)
+}
+
+func TestParseSpans(t *testing.T) {
+	assert.Equal(t, []yt_transcript_models.Span{
+		{Text: "a "},
+		{Text: "b ", Bold: true},
+		{Text: "c", Bold: true, Italic: true},
+		{Text: " d"},
+	}, parseSpans("a <b>b <i>c</i></b></b> <mark>d</mark>"))
+	assert.Nil(t, parseSpans(""))
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"sync"
+
+	yt_errors "github.com/horiagug/youtube-transcript-api-go/pkg/errors"
+)
+
+// ProxyStrategy decides which proxy of a pool serves the next request.
+type ProxyStrategy int
+
+const (
+	RoundRobin ProxyStrategy = iota
+	RandomProxy
+)
+
+const defaultMaxProxyFailures = 3
+
+// ProxyPool rotates requests over several proxies. A proxy answering with too many
+// consecutive 429s or connection errors is ejected from the pool.
+type ProxyPool struct {
+	strategy    ProxyStrategy
+	maxFailures int
+
+	mu      sync.Mutex
+	proxies []*poolProxy
+	next    int
+}
+
+type poolProxy struct {
+	url      *url.URL
+	failures int
+	ejected  bool
+}
+
+type ProxyPoolOption func(*ProxyPool)
+
+func WithProxyStrategy(strategy ProxyStrategy) ProxyPoolOption {
+	return func(p *ProxyPool) {
+		p.strategy = strategy
+	}
+}
+
+// WithMaxProxyFailures sets after how many consecutive failures a proxy is ejected.
+func WithMaxProxyFailures(failures int) ProxyPoolOption {
+	return func(p *ProxyPool) {
+		p.maxFailures = failures
+	}
+}
+
+// ParseProxyURL parses an http, https, socks5 or socks5h proxy URL.
+func ParseProxyURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL %q: %w", rawURL, err)
+	}
+
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q in %q", u.Scheme, rawURL)
+	}
+
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: missing host", rawURL)
+	}
+	return u, nil
+}
+
+func NewProxyPool(proxyURLs []string, options ...ProxyPoolOption) (*ProxyPool, error) {
+	if len(proxyURLs) == 0 {
+		return nil, fmt.Errorf("proxy pool needs at least one proxy")
+	}
+
+	pool := &ProxyPool{
+		strategy:    RoundRobin,
+		maxFailures: defaultMaxProxyFailures,
+		proxies:     make([]*poolProxy, 0, len(proxyURLs)),
+	}
+
+	for _, rawURL := range proxyURLs {
+		u, err := ParseProxyURL(rawURL)
+		if err != nil {
+			return nil, err
+		}
+		pool.proxies = append(pool.proxies, &poolProxy{url: u})
+	}
+
+	for _, opt := range options {
+		opt(pool)
+	}
+	if pool.maxFailures < 1 {
+		pool.maxFailures = 1
+	}
+
+	return pool, nil
+}
+
+// Available returns the URLs of the proxies that have not been ejected.
+func (p *ProxyPool) Available() []*url.URL {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	available := make([]*url.URL, 0, len(p.proxies))
+	for _, proxy := range p.proxies {
+		if !proxy.ejected {
+			available = append(available, proxy.url)
+		}
+	}
+	return available
+}
+
+func (p *ProxyPool) pick() (*poolProxy, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	active := make([]*poolProxy, 0, len(p.proxies))
+	for _, proxy := range p.proxies {
+		if !proxy.ejected {
+			active = append(active, proxy)
+		}
+	}
+	if len(active) == 0 {
+		return nil, yt_errors.ErrNoProxyAvailable
+	}
+
+	if p.strategy == RandomProxy {
+		return active[rand.IntN(len(active))], nil
+	}
+
+	proxy := active[p.next%len(active)]
+	p.next++
+	return proxy, nil
+}
+
+func (p *ProxyPool) report(proxy *poolProxy, failed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !failed {
+		proxy.failures = 0
+		return
+	}
+
+	proxy.failures++
+	if proxy.failures >= p.maxFailures {
+		proxy.ejected = true
+	}
+}
+
+type proxyContextKey struct{}
+
+// proxyPoolTransport picks a proxy per request and passes it to the wrapped transport
+// through the request context, so the outcome can be attributed to that proxy.
+type proxyPoolTransport struct {
+	pool *ProxyPool
+	base *http.Transport
+}
+
+func (p *ProxyPool) wrap(base *http.Transport) http.RoundTripper {
+	base.Proxy = func(req *http.Request) (*url.URL, error) {
+		proxy, _ := req.Context().Value(proxyContextKey{}).(*poolProxy)
+		if proxy == nil {
+			return nil, nil
+		}
+		return proxy.url, nil
+	}
+	return &proxyPoolTransport{pool: p, base: base}
+}
+
+func (t *proxyPoolTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	proxy, err := t.pool.pick()
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.WithContext(context.WithValue(req.Context(), proxyContextKey{}, proxy))
+	resp, err := t.base.RoundTrip(req)
+
+	// Cancellations say nothing about the proxy
+	if err != nil && req.Context().Err() != nil {
+		return resp, err
+	}
+	t.pool.report(proxy, err != nil || resp.StatusCode == http.StatusTooManyRequests)
+
+	return resp, err
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket throttling requests to requestsPerSecond with bursts of up
+// to burst requests. A per-host limiter keeps a separate bucket for every host.
+// It is safe for concurrent use and can be shared by several fetchers.
+type RateLimiter struct {
+	requestsPerSecond float64
+	burst             int
+	perHost           bool
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	return newRateLimiter(requestsPerSecond, burst, false)
+}
+
+func NewPerHostRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	return newRateLimiter(requestsPerSecond, burst, true)
+}
+
+func newRateLimiter(requestsPerSecond float64, burst int, perHost bool) *RateLimiter {
+	return &RateLimiter{
+		requestsPerSecond: requestsPerSecond,
+		burst:             max(burst, 1),
+		perHost:           perHost,
+		buckets:           make(map[string]*tokenBucket),
+	}
+}
+
+// Wait blocks until a request to host may be sent or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context, host string) error {
+	if l == nil || l.requestsPerSecond <= 0 {
+		return ctx.Err()
+	}
+
+	delay, bucket := l.reserve(host)
+	if err := sleepContext(ctx, delay); err != nil {
+		// Hand the reserved token back, the request is never sent
+		l.mu.Lock()
+		bucket.tokens++
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// reserve takes a token, letting the bucket go negative, and returns how long to wait for it.
+func (l *RateLimiter) reserve(host string) (time.Duration, *tokenBucket) {
+	if !l.perHost {
+		host = ""
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	bucket, ok := l.buckets[host]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(l.burst), last: now}
+		l.buckets[host] = bucket
+	}
+
+	bucket.tokens = min(float64(l.burst), bucket.tokens+now.Sub(bucket.last).Seconds()*l.requestsPerSecond)
+	bucket.last = now
+	bucket.tokens--
+
+	if bucket.tokens >= 0 {
+		return 0, bucket
+	}
+	return time.Duration(-bucket.tokens / l.requestsPerSecond * float64(time.Second)), bucket
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiterBurstThenThrottles(t *testing.T) {
+	limiter := NewRateLimiter(20, 2)
+	ctx := context.Background()
+
+	start := time.Now()
+	for range 4 {
+		assert.NoError(t, limiter.Wait(ctx, "www.youtube.com"))
+	}
+	elapsed := time.Since(start)
+
+	// Two requests are free, the next two wait 50ms each
+	assert.GreaterOrEqual(t, elapsed, 90*time.Millisecond)
+	assert.Less(t, elapsed, 500*time.Millisecond)
+}
+
+func TestPerHostRateLimiter(t *testing.T) {
+	limiter := NewPerHostRateLimiter(1, 1)
+	ctx := context.Background()
+
+	start := time.Now()
+	assert.NoError(t, limiter.Wait(ctx, "www.youtube.com"))
+	assert.NoError(t, limiter.Wait(ctx, "youtubei.googleapis.com"))
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestRateLimiterHonorsContext(t *testing.T) {
+	limiter := NewRateLimiter(0.1, 1)
+	assert.NoError(t, limiter.Wait(context.Background(), ""))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, limiter.Wait(ctx, ""), context.DeadlineExceeded)
+}
+
+func TestFetcherUsesRateLimiter(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	fetcher := NewHTMLFetcher(WithRateLimiter(NewRateLimiter(20, 1)))
+
+	start := time.Now()
+	for range 3 {
+		_, err := fetcher.FetchWithContext(context.Background(), server.URL, nil)
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(3), hits.Load())
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
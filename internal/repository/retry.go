@@ -0,0 +1,281 @@
+package repository
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	yt_errors "github.com/horiagug/youtube-transcript-api-go/pkg/errors"
+)
+
+// RetryPolicy controls how failed requests are retried. Network errors, empty bodies and
+// the RetryableStatusCodes are retried with exponential backoff: the n-th retry waits
+// BaseDelay * 2^(n-1), capped at MaxDelay and randomized by +/- Jitter (0 to 1).
+type RetryPolicy struct {
+	MaxAttempts          int
+	BaseDelay            time.Duration
+	MaxDelay             time.Duration
+	Jitter               float64
+	RetryableStatusCodes []int
+	// RespectRetryAfter waits at least as long as a 429's Retry-After header asks for.
+	// When that is longer than MaxDelay the request fails with TooManyRequestsError instead.
+	RespectRetryAfter bool
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Second,
+		MaxDelay:    30 * time.Second,
+		Jitter:      0.2,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RespectRetryAfter: true,
+	}
+}
+
+// backoff returns the delay before the given retry, starting at 1.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < retry && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		delay = time.Duration(float64(delay) * (1 + p.Jitter*(2*rand.Float64()-1)))
+	}
+	return delay
+}
+
+func (p RetryPolicy) retryable(statusCode int) bool {
+	return slices.Contains(p.RetryableStatusCodes, statusCode)
+}
+
+// sleepContext waits for d or until ctx is done, whichever comes first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// doWithRetry sends the request built by newRequest until it succeeds or the retry policy
+// gives up, and returns the response body. newRequest is called for every attempt so
+// request bodies can be replayed.
+func (f *HTMLFetcher) doWithRetry(ctx context.Context, newRequest func() (*http.Request, error)) ([]byte, error) {
+	var body []byte
+	err := f.retry(ctx, f.client, 0, newRequest, func(r io.Reader) error {
+		var err error
+		body, err = io.ReadAll(r)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// streamWithRetry is doWithRetry handing the response body to consume instead of reading it
+// into memory. Failing to read the body is retried, calling consume again from the start,
+// any other error returned by consume is returned right away.
+//
+// The client timeout would also bound the time consume spends between reads, so it only
+// applies to getting the response and to every single read of the body instead.
+func (f *HTMLFetcher) streamWithRetry(ctx context.Context, newRequest func() (*http.Request, error), consume func(io.Reader) error) error {
+	return f.retry(ctx, f.streamClient, f.client.Timeout, newRequest, consume)
+}
+
+// retry sends requests with client until one succeeds or the retry policy gives up. A positive
+// idleTimeout cancels attempts waiting longer than that for the response or a read of its body.
+func (f *HTMLFetcher) retry(ctx context.Context, client *http.Client, idleTimeout time.Duration, newRequest func() (*http.Request, error), consume func(io.Reader) error) error {
+	attempts := max(f.retryPolicy.MaxAttempts, 1)
+	var lastErr error
+
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			delay := f.retryPolicy.backoff(attempt - 1)
+
+			var rateLimited *yt_errors.TooManyRequestsError
+			if errors.As(lastErr, &rateLimited) && f.retryPolicy.RespectRetryAfter && rateLimited.RetryAfter > 0 {
+				if f.retryPolicy.MaxDelay > 0 && rateLimited.RetryAfter > f.retryPolicy.MaxDelay {
+					return lastErr
+				}
+				delay = max(delay, rateLimited.RetryAfter)
+			}
+
+			f.logger.Debug("waiting before retry", "attempt", attempt, "delay", delay)
+			if err := sleepContext(ctx, delay); err != nil {
+				return fmt.Errorf("%w (last error: %v)", err, lastErr)
+			}
+		}
+
+		req, err := newRequest()
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+
+		if err := f.rateLimiter.Wait(ctx, req.URL.Host); err != nil {
+			return fmt.Errorf("rate limiter: %w", err)
+		}
+
+		status, retry, err := f.attempt(client, idleTimeout, req, consume)
+		if err == nil {
+			return nil
+		}
+		if !retry || ctx.Err() != nil {
+			f.logger.Debug("request failed", "method", req.Method, "url", redactURL(req.URL), "attempt", attempt, "status", status, "error", err)
+			return err
+		}
+
+		f.logger.Warn("request failed, retrying", "method", req.Method, "url", redactURL(req.URL), "attempt", attempt, "max_attempts", attempts, "status", status, "error", err)
+		lastErr = err
+	}
+
+	var rateLimited *yt_errors.TooManyRequestsError
+	if errors.As(lastErr, &rateLimited) {
+		return lastErr
+	}
+	return fmt.Errorf("failed to fetch after %d attempts: %w", attempts, lastErr)
+}
+
+// attempt sends a single request, hands its body to consume and reports the status code
+// and whether a failure is worth retrying.
+func (f *HTMLFetcher) attempt(client *http.Client, idleTimeout time.Duration, req *http.Request, consume func(io.Reader) error) (int, bool, error) {
+	var watchdog *idleWatchdog
+	if idleTimeout > 0 {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		req = req.WithContext(ctx)
+		watchdog = newIdleWatchdog(idleTimeout, cancel)
+		defer watchdog.stop()
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if watchdog.fired() {
+			err = fmt.Errorf("no response within %s: %w", idleTimeout, err)
+		}
+		return 0, !errors.Is(err, yt_errors.ErrNoProxyAvailable), fmt.Errorf("failed to execute HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return resp.StatusCode, f.retryPolicy.retryable(resp.StatusCode), tooManyRequests(req.URL.String(), resp)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, f.retryPolicy.retryable(resp.StatusCode), fmt.Errorf("received non-OK status code: %d", resp.StatusCode)
+	}
+
+	var reader io.Reader = resp.Body
+	if watchdog != nil {
+		watchdog.stop()
+		reader = &idleReader{r: resp.Body, watchdog: watchdog}
+	}
+
+	body := &bodyReader{r: bufio.NewReader(reader)}
+	if _, err := body.r.Peek(1); err == io.EOF {
+		return resp.StatusCode, true, fmt.Errorf("empty response body")
+	}
+
+	if err := consume(body); err != nil {
+		if body.err != nil {
+			return resp.StatusCode, true, fmt.Errorf("failed to read response body: %w", body.err)
+		}
+		return resp.StatusCode, false, err
+	}
+
+	return resp.StatusCode, false, nil
+}
+
+// bodyReader remembers read errors of the response body, telling them apart from errors
+// of the body's consumer.
+type bodyReader struct {
+	r   *bufio.Reader
+	err error
+}
+
+func (b *bodyReader) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	if err != nil && err != io.EOF {
+		b.err = err
+	}
+	return n, err
+}
+
+// idleWatchdog cancels a request once it has been waiting for longer than timeout.
+type idleWatchdog struct {
+	timeout time.Duration
+	timer   *time.Timer
+	expired atomic.Bool
+}
+
+func newIdleWatchdog(timeout time.Duration, cancel context.CancelFunc) *idleWatchdog {
+	w := &idleWatchdog{timeout: timeout}
+	w.timer = time.AfterFunc(timeout, func() {
+		w.expired.Store(true)
+		cancel()
+	})
+	return w
+}
+
+func (w *idleWatchdog) start() {
+	w.timer.Reset(w.timeout)
+}
+
+func (w *idleWatchdog) stop() {
+	w.timer.Stop()
+}
+
+func (w *idleWatchdog) fired() bool {
+	return w != nil && w.expired.Load()
+}
+
+// idleReader runs the watchdog only while waiting for the body, not while its reader is busy.
+type idleReader struct {
+	r        io.Reader
+	watchdog *idleWatchdog
+}
+
+func (r *idleReader) Read(p []byte) (int, error) {
+	r.watchdog.start()
+	n, err := r.r.Read(p)
+	r.watchdog.stop()
+
+	if err != nil && err != io.EOF && r.watchdog.fired() {
+		err = fmt.Errorf("no data within %s: %w", r.watchdog.timeout, err)
+	}
+	return n, err
+}
+
+// redactURL drops the query, which holds API keys and caption signatures, from logged URLs.
+func redactURL(u *url.URL) string {
+	redacted := *u
+	redacted.RawQuery = ""
+	return redacted.String()
+}
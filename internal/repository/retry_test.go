@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	yt_errors "github.com/horiagug/youtube-transcript-api-go/pkg/errors"
+)
+
+var fastRetryPolicy = RetryPolicy{
+	MaxAttempts:          3,
+	BaseDelay:            time.Millisecond,
+	MaxDelay:             10 * time.Millisecond,
+	RetryableStatusCodes: []int{http.StatusTooManyRequests, http.StatusServiceUnavailable},
+	RespectRetryAfter:    true,
+}
+
+// statusServer answers with the given status codes in order, then with 200.
+func statusServer(t *testing.T, hits *atomic.Int32, statuses ...int) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := int(hits.Add(1)) - 1
+		if i < len(statuses) {
+			w.WriteHeader(statuses[i])
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRetryRecoversFromRetryableStatus(t *testing.T) {
+	var hits atomic.Int32
+	server := statusServer(t, &hits, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+
+	fetcher := NewHTMLFetcher(WithRetryPolicy(fastRetryPolicy))
+	body, err := fetcher.FetchWithContext(context.Background(), server.URL, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", string(body))
+	assert.Equal(t, int32(3), hits.Load())
+}
+
+func TestRetryLogsAttempts(t *testing.T) {
+	var hits atomic.Int32
+	server := statusServer(t, &hits, http.StatusServiceUnavailable)
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+	fetcher := NewHTMLFetcher(WithRetryPolicy(fastRetryPolicy), WithLogger(logger))
+	_, err := fetcher.FetchWithContext(context.Background(), server.URL+"/api?key=secret", nil)
+
+	assert.NoError(t, err)
+	assert.Contains(t, logs.String(), "attempt=1")
+	assert.Contains(t, logs.String(), "status=503")
+	assert.Contains(t, logs.String(), "url="+server.URL+"/api")
+	assert.NotContains(t, logs.String(), "secret")
+}
+
+func TestRetrySkipsNonRetryableStatus(t *testing.T) {
+	var hits atomic.Int32
+	server := statusServer(t, &hits, http.StatusNotFound)
+
+	fetcher := NewHTMLFetcher(WithRetryPolicy(fastRetryPolicy))
+	_, err := fetcher.FetchWithContext(context.Background(), server.URL, nil)
+
+	assert.ErrorContains(t, err, "404")
+	assert.Equal(t, int32(1), hits.Load())
+}
+
+func TestRetryKeepsLastError(t *testing.T) {
+	var hits atomic.Int32
+	server := statusServer(t, &hits, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+
+	fetcher := NewHTMLFetcher(WithRetryPolicy(fastRetryPolicy))
+	_, err := fetcher.FetchWithContext(context.Background(), server.URL, nil)
+
+	assert.ErrorContains(t, err, "failed to fetch after 3 attempts: received non-OK status code: 503")
+	assert.Equal(t, int32(3), hits.Load())
+}
+
+func TestRetryAfterLongerThanMaxDelay(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	fetcher := NewHTMLFetcher(WithRetryPolicy(fastRetryPolicy))
+	_, err := fetcher.FetchWithContext(context.Background(), server.URL, nil)
+
+	var rateLimited *yt_errors.TooManyRequestsError
+	assert.True(t, errors.As(err, &rateLimited))
+	assert.Equal(t, 120*time.Second, rateLimited.RetryAfter)
+	assert.Equal(t, int32(1), hits.Load())
+}
+
+func TestRetryHonorsContextCancellation(t *testing.T) {
+	var hits atomic.Int32
+	server := statusServer(t, &hits, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+
+	policy := fastRetryPolicy
+	policy.BaseDelay = time.Minute
+	policy.MaxDelay = time.Minute
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := NewHTMLFetcher(WithRetryPolicy(policy)).FetchWithContext(ctx, server.URL, nil)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestRetryInnertubePost(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"ok": true}`))
+	}))
+	defer server.Close()
+
+	fetcher := NewHTMLFetcher(WithRetryPolicy(fastRetryPolicy))
+	body, err := fetcher.doWithRetry(context.Background(), func() (*http.Request, error) {
+		return fetcher.newRequest(context.Background(), "POST", server.URL, nil)
+	})
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"ok": true}`, string(body))
+	assert.Equal(t, int32(2), hits.Load())
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+
+	assert.Equal(t, time.Second, policy.backoff(1))
+	assert.Equal(t, 2*time.Second, policy.backoff(2))
+	assert.Equal(t, 4*time.Second, policy.backoff(3))
+	assert.Equal(t, 5*time.Second, policy.backoff(4))
+
+	policy.Jitter = 0.5
+	for range 100 {
+		delay := policy.backoff(1)
+		assert.GreaterOrEqual(t, delay, 500*time.Millisecond)
+		assert.LessOrEqual(t, delay, 1500*time.Millisecond)
+	}
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_models"
+)
+
+// parseSpans splits text holding the normalized formatting tags left by cleanHTML into spans. Tags other than
+// bold, italic and underline are dropped, unbalanced closing tags are ignored.
+func parseSpans(text string) []yt_transcript_models.Span {
+	var spans []yt_transcript_models.Span
+	var bold, italic, underline int
+
+	appendText := func(s string) {
+		if s == "" {
+			return
+		}
+		span := yt_transcript_models.Span{Text: s, Bold: bold > 0, Italic: italic > 0, Underline: underline > 0}
+
+		// Adjacent text with the same formatting, e.g. around a dropped tag, forms one span
+		if last := len(spans) - 1; last >= 0 {
+			previous := spans[last]
+			if previous.Bold == span.Bold && previous.Italic == span.Italic && previous.Underline == span.Underline {
+				spans[last].Text += s
+				return
+			}
+		}
+		spans = append(spans, span)
+	}
+
+	last := 0
+	for _, m := range htmlRegex.FindAllStringIndex(text, -1) {
+		appendText(text[last:m[0]])
+		last = m[1]
+
+		tag := strings.Trim(text[m[0]:m[1]], "<>")
+		closing := strings.HasPrefix(tag, "/")
+		var counter *int
+		switch strings.TrimPrefix(tag, "/") {
+		case "b", "strong":
+			counter = &bold
+		case "i", "em":
+			counter = &italic
+		case "u", "ins":
+			counter = &underline
+		default:
+			continue
+		}
+
+		if !closing {
+			*counter++
+		} else if *counter > 0 {
+			*counter--
+		}
+	}
+	appendText(text[last:])
+
+	return spans
+}
@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"iter"
+	"strconv"
+	"strings"
+
+	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_models"
+)
+
+// formatPeekSize is how much of a caption track is buffered to detect its format
+const formatPeekSize = 512
+
+// Lines streams the lines of a caption track in any of the caption formats. The XML formats are
+// decoded token by token, so a multi-hour track is never held in memory as a whole; json3 has
+// no such decoder and is decoded at once. A decoding error is yielded last.
+func (p *transcriptParser) Lines(r io.Reader) iter.Seq2[yt_transcript_models.TranscriptLine, error] {
+	return func(yield func(yt_transcript_models.TranscriptLine, error) bool) {
+		reader := bufio.NewReader(r)
+		head, _ := reader.Peek(formatPeekSize)
+
+		if detectCaptionFormat(head) == CaptionFormatJSON3 {
+			lines, err := p.parseJSON3(reader)
+			if err != nil {
+				yield(yt_transcript_models.TranscriptLine{}, err)
+				return
+			}
+			for _, line := range lines {
+				if !yield(line, nil) {
+					return
+				}
+			}
+			return
+		}
+
+		if err := p.decodeXML(reader, yield); err != nil {
+			yield(yt_transcript_models.TranscriptLine{}, err)
+		}
+	}
+}
+
+// ParseReader collects the lines of a caption track read from r.
+func (p *transcriptParser) ParseReader(r io.Reader) ([]yt_transcript_models.TranscriptLine, error) {
+	var results []yt_transcript_models.TranscriptLine
+	for line, err := range p.Lines(r) {
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, line)
+	}
+	return results, nil
+}
+
+// decodeXML decodes <transcript><text> and <timedtext><p> tracks, it stops without an error
+// once yield returns false.
+func (p *transcriptParser) decodeXML(r io.Reader, yield func(yt_transcript_models.TranscriptLine, error) bool) error {
+	decoder := xml.NewDecoder(r)
+
+	root := ""
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			if root == "" {
+				return io.ErrUnexpectedEOF
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		if root == "" {
+			root = start.Name.Local
+			if root != "transcript" && root != "timedtext" {
+				return fmt.Errorf("expected element type <transcript> or <timedtext> but have <%s>", root)
+			}
+			continue
+		}
+
+		var line yt_transcript_models.TranscriptLine
+		switch {
+		case root == "transcript" && start.Name.Local == "text":
+			line, err = p.decodeText(decoder, start)
+			ok = true
+		case root == "timedtext" && start.Name.Local == "p":
+			line, ok, err = p.decodeParagraph(decoder, start)
+		default:
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if ok && !yield(line, nil) {
+			return nil
+		}
+	}
+}
+
+// decodeText decodes a legacy <text start=.. dur=..> element.
+func (p *transcriptParser) decodeText(decoder *xml.Decoder, start xml.StartElement) (yt_transcript_models.TranscriptLine, error) {
+	raw, err := elementText(decoder)
+	if err != nil {
+		return yt_transcript_models.TranscriptLine{}, err
+	}
+
+	text, spans := p.lineText(raw)
+	return yt_transcript_models.TranscriptLine{
+		Text:     text,
+		Start:    floatAttr(start, "start"),
+		Duration: floatAttr(start, "dur"),
+		Spans:    spans,
+	}, nil
+}
+
+// decodeParagraph decodes a srv3 <p t=.. d=..> element. Manually created tracks have the text
+// directly in <p>, auto-generated ones in timed <s> segments.
+func (p *transcriptParser) decodeParagraph(decoder *xml.Decoder, start xml.StartElement) (yt_transcript_models.TranscriptLine, bool, error) {
+	var text strings.Builder
+	var segments []captionSegment
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return yt_transcript_models.TranscriptLine{}, false, err
+		}
+
+		switch t := token.(type) {
+		case xml.CharData:
+			text.Write(t)
+		case xml.StartElement:
+			if t.Name.Local != "s" {
+				if err := decoder.Skip(); err != nil {
+					return yt_transcript_models.TranscriptLine{}, false, err
+				}
+				continue
+			}
+			segmentText, err := elementText(decoder)
+			if err != nil {
+				return yt_transcript_models.TranscriptLine{}, false, err
+			}
+			segments = append(segments, captionSegment{text: segmentText, offset: intAttr(t, "t")})
+		case xml.EndElement:
+			timedWords := len(segments) > 0
+			if !timedWords {
+				segments = []captionSegment{{text: text.String()}}
+			}
+			line, ok := p.captionLine(intAttr(start, "t"), intAttr(start, "d"), segments, timedWords)
+			return line, ok, nil
+		}
+	}
+}
+
+// elementText reads the character data up to the end of the current element, nested
+// elements included.
+func elementText(decoder *xml.Decoder) (string, error) {
+	var text strings.Builder
+	for depth := 0; ; {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", err
+		}
+
+		switch t := token.(type) {
+		case xml.CharData:
+			text.Write(t)
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			if depth == 0 {
+				return text.String(), nil
+			}
+			depth--
+		}
+	}
+}
+
+func floatAttr(element xml.StartElement, name string) float64 {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name {
+			value, err := strconv.ParseFloat(attr.Value, 64)
+			if err != nil {
+				return 0.0
+			}
+			return value
+		}
+	}
+	return 0.0
+}
+
+func intAttr(element xml.StartElement, name string) int64 {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name {
+			value, err := strconv.ParseInt(attr.Value, 10, 64)
+			if err != nil {
+				return 0
+			}
+			return value
+		}
+	}
+	return 0
+}
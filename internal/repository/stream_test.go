@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const streamTranscript = `<?xml version="1.0" encoding="utf-8" ?><transcript>
+	<text start="0" dur="1.5">first</text>
+	<text start="1.5" dur="2">second</text>
+	<text start="3.5" dur="1">third</text>
+</transcript>`
+
+func TestLinesStopsEarly(t *testing.T) {
+	parser := NewTranscriptParser(false)
+
+	var texts []string
+	for line, err := range parser.Lines(strings.NewReader(streamTranscript)) {
+		assert.NoError(t, err)
+		texts = append(texts, line.Text)
+		if len(texts) == 2 {
+			break
+		}
+	}
+
+	assert.Equal(t, []string{"first", "second"}, texts)
+}
+
+func TestLinesYieldsErrorLast(t *testing.T) {
+	parser := NewTranscriptParser(false)
+	truncated := streamTranscript[:strings.Index(streamTranscript, "third")]
+
+	var texts []string
+	var lastErr error
+	for line, err := range parser.Lines(strings.NewReader(truncated)) {
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		texts = append(texts, line.Text)
+	}
+
+	assert.Equal(t, []string{"first", "second"}, texts)
+	assert.Error(t, lastErr)
+}
+
+func TestLinesUnexpectedRoot(t *testing.T) {
+	parser := NewTranscriptParser(false)
+
+	_, err := parser.ParseReader(strings.NewReader(`<html><body>not captions</body></html>`))
+	assert.ErrorContains(t, err, "<html>")
+
+	_, err = parser.ParseReader(strings.NewReader(`<?xml version="1.0" encoding="utf-8" ?>`))
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestFetchStreamRetriesTruncatedBody(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) == 1 {
+			// Announces more than is sent, the client fails reading the rest
+			w.Header().Set("Content-Length", "4096")
+			w.Write([]byte(streamTranscript[:64]))
+			return
+		}
+		w.Write([]byte(streamTranscript))
+	}))
+	defer server.Close()
+
+	fetcher := NewHTMLFetcher(WithRetryPolicy(fastRetryPolicy))
+	parser := NewTranscriptParser(false)
+
+	consumed := 0
+	var lines int
+	err := fetcher.FetchStreamWithContext(context.Background(), server.URL, nil, func(r io.Reader) error {
+		consumed++
+		transcript, err := parser.ParseReader(r)
+		lines = len(transcript)
+		return err
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, consumed)
+	assert.Equal(t, 3, lines)
+	assert.Equal(t, int32(2), hits.Load())
+}
+
+func TestFetchStreamReturnsConsumerError(t *testing.T) {
+	var hits atomic.Int32
+	server := statusServer(t, &hits)
+
+	fetcher := NewHTMLFetcher(WithRetryPolicy(fastRetryPolicy))
+	consumerErr := errors.New("not a transcript")
+
+	err := fetcher.FetchStreamWithContext(context.Background(), server.URL, nil, func(r io.Reader) error {
+		return consumerErr
+	})
+
+	assert.ErrorIs(t, err, consumerErr)
+	assert.Equal(t, int32(1), hits.Load())
+}
+
+func TestFetchStreamWaitsForSlowConsumer(t *testing.T) {
+	var hits atomic.Int32
+	server := statusServer(t, &hits)
+
+	// The client timeout bounds every read, not the time spent consuming the body
+	fetcher := NewHTMLFetcher(WithHTTPClient(&http.Client{Timeout: 50 * time.Millisecond}), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+
+	err := fetcher.FetchStreamWithContext(context.Background(), server.URL, nil, func(r io.Reader) error {
+		time.Sleep(150 * time.Millisecond)
+		body, err := io.ReadAll(r)
+		assert.Equal(t, "ok", string(body))
+		return err
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), hits.Load())
+}
+
+func TestFetchStreamTimesOutStalledBody(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<transcript>"))
+		w.(http.Flusher).Flush()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	fetcher := NewHTMLFetcher(WithHTTPClient(&http.Client{Timeout: 50 * time.Millisecond}), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+
+	err := fetcher.FetchStreamWithContext(context.Background(), server.URL, nil, func(r io.Reader) error {
+		_, err := io.ReadAll(r)
+		return err
+	})
+
+	assert.ErrorContains(t, err, "no data within 50ms")
+}
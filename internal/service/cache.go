@@ -0,0 +1,96 @@
+package service
+
+import (
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"github.com/horiagug/youtube-transcript-api-go/internal/repository"
+	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_cache"
+	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_models"
+)
+
+const (
+	// Caption URLs in the track list are signed and expire, so the list is kept shorter
+	defaultTrackListTTL = time.Hour
+	defaultCaptionTTL   = 7 * 24 * time.Hour
+)
+
+func WithCache(cache yt_transcript_cache.Cache) Option {
+	return func(t *transcriptService) {
+		t.cache = cache
+	}
+}
+
+// WithCacheTTL sets how long track lists and caption bodies are cached, zero never expires.
+func WithCacheTTL(trackList, captions time.Duration) Option {
+	return func(t *transcriptService) {
+		t.trackListTTL = trackList
+		t.captionTTL = captions
+	}
+}
+
+func trackListCacheKey(videoID string) string {
+	return "tracks/" + videoID
+}
+
+// captionCacheKey identifies a caption body by video, source language, kind, translation and format.
+func captionCacheKey(videoID string, track yt_transcript_models.CaptionTrack, format repository.CaptionFormat) string {
+	kind := "manual"
+	if isGenerated(track) {
+		kind = "asr"
+	}
+
+	language := track.LanguageCode
+	translation := "original"
+	if u, err := url.Parse(track.BaseUrl); err == nil {
+		query := u.Query()
+		if tlang := query.Get("tlang"); tlang != "" {
+			translation = tlang
+			// Translated tracks carry the target language, the source is in the URL
+			if lang := query.Get("lang"); lang != "" {
+				language = lang
+			}
+		}
+	}
+
+	key := "captions/" + videoID + "/" + language + "/" + kind + "/" + translation
+	// Keys of the legacy format predate the others and stay unchanged. The format is part of
+	// the last segment, so no key is the parent directory of another in a FileCache.
+	if format != repository.CaptionFormatXML {
+		key += "." + string(format)
+	}
+	return key
+}
+
+func (t *transcriptService) cachedTrackList(videoID string) (*yt_transcript_models.VideoTranscriptData, bool) {
+	if t.cache == nil {
+		return nil, false
+	}
+
+	data, ok := t.cache.Get(trackListCacheKey(videoID))
+	if !ok {
+		return nil, false
+	}
+
+	var transcriptData yt_transcript_models.VideoTranscriptData
+	if err := json.Unmarshal(data, &transcriptData); err != nil || transcriptData.Transcripts == nil {
+		return nil, false
+	}
+	return &transcriptData, true
+}
+
+func (t *transcriptService) cacheTrackList(videoID string, transcriptData *yt_transcript_models.VideoTranscriptData) {
+	if t.cache == nil {
+		return
+	}
+
+	data, err := json.Marshal(transcriptData)
+	if err != nil {
+		return
+	}
+	// A failing cache only costs a refetch next time
+	if err := t.cache.Set(trackListCacheKey(videoID), data, t.trackListTTL); err != nil {
+		t.logger.Warn("failed to cache track list", "video_id", videoID, "error", err)
+	}
+}
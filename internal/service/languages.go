@@ -0,0 +1,73 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_models"
+)
+
+// Match quality of a caption track language against a requested language tag,
+// ordered from no match to best match.
+const (
+	noMatch = iota
+	// The requested tag is more specific than the track, "en-US" requested and "en" available.
+	parentMatch
+	// The track is more specific than the requested tag, "en" requested and "en-GB" available.
+	childMatch
+	exactMatch
+)
+
+// normalizeLanguageTag lower-cases a BCP-47 tag and accepts "_" as subtag separator.
+func normalizeLanguageTag(tag string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+}
+
+// matchLanguage reports how well a track's language code matches a requested BCP-47 tag.
+func matchLanguage(requested, code string) int {
+	requested = normalizeLanguageTag(requested)
+	code = normalizeLanguageTag(code)
+
+	switch {
+	case requested == "" || code == "":
+		return noMatch
+	case requested == code:
+		return exactMatch
+	case strings.HasPrefix(code, requested+"-"):
+		return childMatch
+	case strings.HasPrefix(requested, code+"-"):
+		return parentMatch
+	default:
+		return noMatch
+	}
+}
+
+// bestTrack picks a single track out of tracks matching the same language equally well,
+// preferring a manually created track over an auto-generated one.
+func bestTrack(tracks []yt_transcript_models.CaptionTrack) yt_transcript_models.CaptionTrack {
+	for _, track := range tracks {
+		if !isGenerated(track) {
+			return track
+		}
+	}
+	return tracks[0]
+}
+
+// tracksForLanguage returns the tracks matching lang, keeping only those with the best match quality.
+func tracksForLanguage(lang string, tracks []yt_transcript_models.CaptionTrack) []yt_transcript_models.CaptionTrack {
+	best := noMatch
+	matching := make([]yt_transcript_models.CaptionTrack, 0, 2)
+
+	for _, track := range tracks {
+		quality := matchLanguage(lang, track.LanguageCode)
+		if quality == noMatch || quality < best {
+			continue
+		}
+		if quality > best {
+			best = quality
+			matching = matching[:0]
+		}
+		matching = append(matching, track)
+	}
+
+	return matching
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"iter"
+
+	yt_errors "github.com/horiagug/youtube-transcript-api-go/pkg/errors"
+	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_models"
+)
+
+// TranscriptLines yields the lines of the transcripts of videoID matching languages, machine
+// translated into targetLanguage unless it is empty. Tracks are downloaded one after the other
+// and their lines yielded as they are parsed; the download waits while the loop body runs.
+// An error ends the sequence.
+func (t transcriptService) TranscriptLines(ctx context.Context, videoID string, languages []string, targetLanguage string, preserve_formatting bool) iter.Seq2[yt_transcript_models.VideoLine, error] {
+	return func(yield func(yt_transcript_models.VideoLine, error) bool) {
+		videoID := t.normalizeVideoID(videoID)
+		if videoID == "" {
+			yield(yt_transcript_models.VideoLine{}, yt_errors.ErrInvalidVideoID)
+			return
+		}
+
+		transcript_data, tracks, err := t.matchingTracks(ctx, videoID, languages)
+		if err != nil {
+			yield(yt_transcript_models.VideoLine{VideoID: videoID}, err)
+			return
+		}
+
+		if targetLanguage != "" {
+			translated, err := translateCaptionTrack(videoID, tracks, transcript_data.Transcripts.TranslationLanguages, targetLanguage)
+			if err != nil {
+				yield(yt_transcript_models.VideoLine{VideoID: videoID}, err)
+				return
+			}
+			tracks = []yt_transcript_models.CaptionTrack{translated}
+		}
+
+		for _, track := range tracks {
+			line := yt_transcript_models.VideoLine{
+				VideoID:      videoID,
+				VideoTitle:   transcript_data.Title,
+				Language:     track.Name.SimpleText,
+				LanguageCode: track.LanguageCode,
+				IsGenerated:  isGenerated(track),
+			}
+
+			stopped := false
+			err := t.streamTrackLines(ctx, videoID, track, preserve_formatting, func(transcriptLine yt_transcript_models.TranscriptLine) bool {
+				line.TranscriptLine = transcriptLine
+				if !yield(line, nil) {
+					stopped = true
+					return false
+				}
+				line.Index++
+				return true
+			})
+			if stopped {
+				return
+			}
+			if err != nil {
+				line.TranscriptLine = yt_transcript_models.TranscriptLine{}
+				yield(line, err)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	yt_errors "github.com/horiagug/youtube-transcript-api-go/pkg/errors"
+	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_models"
+)
+
+// The watch page title carries the site name, the player response title doesn't
+const watchPageTitleSuffix = " - YouTube"
+
+// GetVideoMetadata fetches the metadata of a video. It also works for videos without
+// transcripts, but fails for videos that can't be played.
+func (t transcriptService) GetVideoMetadata(ctx context.Context, videoID string) (*yt_transcript_models.VideoMetadata, error) {
+	videoID = t.normalizeVideoID(videoID)
+	if videoID == "" {
+		return nil, yt_errors.ErrInvalidVideoID
+	}
+
+	if cached, ok := t.cachedTrackList(videoID); ok && cached.Metadata != nil {
+		return cached.Metadata, nil
+	}
+
+	_, innertube_data, err := t.fetchPlayerResponse(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	videoDetails, err := extractInnertubeVideoDetails(innertube_data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract video details: %w", err)
+	}
+
+	if err := playabilityError(videoID, videoDetails.PlayabilityStatus); err != nil {
+		return nil, err
+	}
+
+	if videoDetails.Metadata == nil {
+		return nil, &yt_errors.ParseError{What: "video metadata", Err: fmt.Errorf("videoDetails not found")}
+	}
+	return videoDetails.Metadata, nil
+}
+
+// extractVideoMetadata reads the videoDetails section of a player response, completed with the
+// publish date of the microformat section. It returns nil when there are no video details.
+func extractVideoMetadata(data map[string]interface{}) *yt_transcript_models.VideoMetadata {
+	details, ok := data["videoDetails"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	metadata := &yt_transcript_models.VideoMetadata{}
+	metadata.VideoID, _ = details["videoId"].(string)
+	metadata.Title, _ = details["title"].(string)
+	metadata.ChannelID, _ = details["channelId"].(string)
+	metadata.ChannelName, _ = details["author"].(string)
+	metadata.Description, _ = details["shortDescription"].(string)
+	metadata.IsLive, _ = details["isLive"].(bool)
+	metadata.IsLiveContent, _ = details["isLiveContent"].(bool)
+
+	// Numbers are sent as strings
+	if length, ok := details["lengthSeconds"].(string); ok {
+		seconds, _ := strconv.ParseFloat(length, 64)
+		metadata.Duration = seconds
+	}
+	if views, ok := details["viewCount"].(string); ok {
+		metadata.ViewCount, _ = strconv.ParseInt(views, 10, 64)
+	}
+
+	if keywords, ok := details["keywords"].([]interface{}); ok {
+		metadata.Keywords = make([]string, 0, len(keywords))
+		for _, keyword := range keywords {
+			if keywordString, ok := keyword.(string); ok {
+				metadata.Keywords = append(metadata.Keywords, keywordString)
+			}
+		}
+	}
+
+	thumbnail, _ := details["thumbnail"].(map[string]interface{})
+	if thumbnails, ok := thumbnail["thumbnails"].([]interface{}); ok {
+		metadata.Thumbnails = make([]yt_transcript_models.Thumbnail, 0, len(thumbnails))
+		for _, thumb := range thumbnails {
+			if thumbMap, ok := thumb.(map[string]interface{}); ok {
+				url, _ := thumbMap["url"].(string)
+				width, _ := thumbMap["width"].(float64)
+				height, _ := thumbMap["height"].(float64)
+				metadata.Thumbnails = append(metadata.Thumbnails, yt_transcript_models.Thumbnail{URL: url, Width: int(width), Height: int(height)})
+			}
+		}
+	}
+
+	microformat, _ := data["microformat"].(map[string]interface{})
+	renderer, _ := microformat["playerMicroformatRenderer"].(map[string]interface{})
+	if publishDate, ok := renderer["publishDate"].(string); ok {
+		metadata.PublishDate = parsePublishDate(publishDate)
+	}
+
+	return metadata
+}
+
+// parsePublishDate understands both the date only and the full timestamp forms YouTube uses.
+func parsePublishDate(value string) time.Time {
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date
+		}
+	}
+	return time.Time{}
+}
+
+// videoTitle prefers the title of the player response over the one of the watch page.
+func videoTitle(metadata *yt_transcript_models.VideoMetadata, pageTitle string) string {
+	if metadata != nil && metadata.Title != "" {
+		return metadata.Title
+	}
+	return strings.TrimSuffix(pageTitle, watchPageTitleSuffix)
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"strings"
+
+	yt_errors "github.com/horiagug/youtube-transcript-api-go/pkg/errors"
+	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_models"
+)
+
+// extractPlayabilityStatus reads the playabilityStatus of a player response. Mobile clients
+// put the reason in "reason", the web client only renders it in "errorScreen".
+func extractPlayabilityStatus(data map[string]interface{}) yt_transcript_models.PlayabilityStatus {
+	playability, ok := data["playabilityStatus"].(map[string]interface{})
+	if !ok {
+		return yt_transcript_models.PlayabilityStatus{}
+	}
+
+	status := yt_transcript_models.PlayabilityStatus{}
+	status.Status, _ = playability["status"].(string)
+	status.Reason, _ = playability["reason"].(string)
+
+	errorScreen, _ := playability["errorScreen"].(map[string]interface{})
+	renderer, _ := errorScreen["playerErrorMessageRenderer"].(map[string]interface{})
+	if status.Reason == "" {
+		status.Reason = rendererText(renderer["reason"])
+	}
+	status.ErrorScreen = rendererText(renderer["subreason"])
+
+	return status
+}
+
+// rendererText joins a text renderer, either {"simpleText": ...} or {"runs": [{"text": ...}]}.
+func rendererText(value interface{}) string {
+	text, _ := value.(map[string]interface{})
+	if simpleText, ok := text["simpleText"].(string); ok {
+		return simpleText
+	}
+
+	runs, _ := text["runs"].([]interface{})
+	var builder strings.Builder
+	for _, run := range runs {
+		if runMap, ok := run.(map[string]interface{}); ok {
+			runText, _ := runMap["text"].(string)
+			builder.WriteString(runText)
+		}
+	}
+	return builder.String()
+}
+
+// playabilityError maps a playability status that isn't OK to a typed error. YouTube only
+// tells most failures apart in the human readable reason, which is always requested in English.
+func playabilityError(videoID string, status yt_transcript_models.PlayabilityStatus) error {
+	reason := status.Reason
+	if status.ErrorScreen != "" {
+		reason = strings.TrimSpace(reason + " " + status.ErrorScreen)
+	}
+	text := strings.ToLower(reason)
+
+	switch status.Status {
+	case "", "OK":
+		return nil
+	case "AGE_CHECK_REQUIRED", "AGE_VERIFICATION_REQUIRED":
+		return &yt_errors.AgeRestrictedError{VideoID: videoID}
+	case "LIVE_STREAM_OFFLINE":
+		return &yt_errors.LiveStreamError{VideoID: videoID, Reason: reason}
+	}
+
+	switch {
+	case strings.Contains(text, "private"):
+		return &yt_errors.VideoPrivateError{VideoID: videoID}
+	case strings.Contains(text, "confirm your age") || strings.Contains(text, "age-restricted"):
+		return &yt_errors.AgeRestrictedError{VideoID: videoID}
+	case strings.Contains(text, "country") || strings.Contains(text, "region"):
+		return &yt_errors.RegionBlockedError{VideoID: videoID, Reason: reason}
+	case strings.Contains(text, "removed") || strings.Contains(text, "terminated") || strings.Contains(text, "no longer available"):
+		return &yt_errors.VideoRemovedError{VideoID: videoID, Reason: reason}
+	case status.Status == "LOGIN_REQUIRED":
+		return &yt_errors.LoginRequiredError{VideoID: videoID, Reason: reason}
+	}
+	return &yt_errors.VideoUnavailableError{VideoID: videoID, Reason: reason}
+}
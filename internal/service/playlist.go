@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	yt_errors "github.com/horiagug/youtube-transcript-api-go/pkg/errors"
+)
+
+// Upper bound on browse continuations, a page holds 100 videos
+const maxBrowsePages = 500
+
+var (
+	channelIDRegex    = regexp.MustCompile(`^UC[a-zA-Z0-9_-]{22}$`)
+	channelURLIDRegex = regexp.MustCompile(`/channel/(UC[a-zA-Z0-9_-]{22})`)
+)
+
+// ListPlaylistVideos returns the video IDs of a playlist, given its ID or URL.
+func (t transcriptService) ListPlaylistVideos(ctx context.Context, playlist string) ([]string, error) {
+	playlistID := sanitizePlaylistId(playlist)
+	if playlistID == "" {
+		return nil, fmt.Errorf("invalid playlist %q: %w", playlist, yt_errors.ErrPlaylistUnavailable)
+	}
+
+	return t.browseVideoIDs(ctx, playlistID)
+}
+
+// ListChannelVideos returns the video IDs uploaded by a channel, given its handle
+// ("@name"), channel ID or URL.
+func (t transcriptService) ListChannelVideos(ctx context.Context, channel string) ([]string, error) {
+	channelID, err := t.resolveChannelID(ctx, channel)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every channel has an uploads playlist whose ID replaces the "UC" prefix with "UU"
+	return t.browseVideoIDs(ctx, "UU"+strings.TrimPrefix(channelID, "UC"))
+}
+
+func (t transcriptService) resolveChannelID(ctx context.Context, channel string) (string, error) {
+	channel = strings.TrimSpace(channel)
+	if channelIDRegex.MatchString(channel) {
+		return channel, nil
+	}
+	if match := channelURLIDRegex.FindStringSubmatch(channel); match != nil {
+		return match[1], nil
+	}
+
+	data, err := t.fetcher.FetchInnertubeEndpoint(ctx, "navigation/resolve_url", map[string]interface{}{"url": channelURL(channel)})
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve channel %s: %w", channel, err)
+	}
+
+	endpoint, _ := data["endpoint"].(map[string]interface{})
+	browse, _ := endpoint["browseEndpoint"].(map[string]interface{})
+	browseID, _ := browse["browseId"].(string)
+	if !channelIDRegex.MatchString(browseID) {
+		return "", fmt.Errorf("failed to resolve channel %s: %w", channel, yt_errors.ErrChannelNotFound)
+	}
+
+	return browseID, nil
+}
+
+// channelURL turns a handle or channel URL, with or without scheme, into the
+// https://www.youtube.com URL resolved by innertube.
+func channelURL(channel string) string {
+	if !strings.Contains(channel, "/") {
+		return "https://www.youtube.com/@" + strings.TrimPrefix(channel, "@")
+	}
+
+	raw := channel
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return channel
+	}
+
+	switch strings.ToLower(u.Hostname()) {
+	case "youtube.com", "www.youtube.com", "m.youtube.com":
+		return "https://www.youtube.com" + strings.TrimSuffix(u.Path, "/")
+	}
+	return raw
+}
+
+// browseVideoIDs pages through a playlist with the innertube browse endpoint.
+func (t transcriptService) browseVideoIDs(ctx context.Context, playlistID string) ([]string, error) {
+	payload := map[string]interface{}{"browseId": "VL" + playlistID}
+	seen := make(map[string]bool)
+	var videoIDs []string
+
+	for range maxBrowsePages {
+		data, err := t.fetcher.FetchInnertubeEndpoint(ctx, "browse", payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to browse playlist %s: %w", playlistID, err)
+		}
+
+		pageIDs, continuation := extractPlaylistVideos(data)
+		for _, id := range pageIDs {
+			if !seen[id] {
+				seen[id] = true
+				videoIDs = append(videoIDs, id)
+			}
+		}
+
+		if continuation == "" {
+			break
+		}
+		payload = map[string]interface{}{"continuation": continuation}
+	}
+
+	if len(videoIDs) == 0 {
+		return nil, fmt.Errorf("no videos found in playlist %s: %w", playlistID, yt_errors.ErrPlaylistUnavailable)
+	}
+
+	return videoIDs, nil
+}
+
+// extractPlaylistVideos walks a browse response collecting the video IDs of
+// playlistVideoRenderer items and the continuation token of the next page, if any.
+func extractPlaylistVideos(data interface{}) ([]string, string) {
+	var (
+		videoIDs     []string
+		continuation string
+	)
+
+	var walk func(interface{})
+	walk = func(node interface{}) {
+		switch value := node.(type) {
+		case map[string]interface{}:
+			if renderer, ok := value["playlistVideoRenderer"].(map[string]interface{}); ok {
+				if id, ok := renderer["videoId"].(string); ok {
+					videoIDs = append(videoIDs, id)
+				}
+				return
+			}
+			if renderer, ok := value["continuationItemRenderer"].(map[string]interface{}); ok {
+				endpoint, _ := renderer["continuationEndpoint"].(map[string]interface{})
+				command, _ := endpoint["continuationCommand"].(map[string]interface{})
+				if token, ok := command["token"].(string); ok {
+					continuation = token
+				}
+				return
+			}
+			for _, child := range value {
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range value {
+				walk(child)
+			}
+		}
+	}
+
+	walk(data)
+	return videoIDs, continuation
+}
+
+func sanitizePlaylistId(playlist string) string {
+	playlist = strings.TrimSpace(playlist)
+	if strings.Contains(playlist, "list=") {
+		if !strings.Contains(playlist, "://") {
+			playlist = "https://" + playlist
+		}
+		u, err := url.Parse(playlist)
+		if err != nil {
+			return ""
+		}
+		return u.Query().Get("list")
+	}
+	return playlist
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/horiagug/youtube-transcript-api-go/internal/repository/fixtures"
+	yt_errors "github.com/horiagug/youtube-transcript-api-go/pkg/errors"
+)
+
+func playlistPage(continuation string, videoIDs ...string) map[string]interface{} {
+	items := make([]interface{}, 0, len(videoIDs)+1)
+	for _, id := range videoIDs {
+		items = append(items, map[string]interface{}{
+			"playlistVideoRenderer": map[string]interface{}{"videoId": id},
+		})
+	}
+	if continuation != "" {
+		items = append(items, map[string]interface{}{
+			"continuationItemRenderer": map[string]interface{}{
+				"continuationEndpoint": map[string]interface{}{
+					"continuationCommand": map[string]interface{}{"token": continuation},
+				},
+			},
+		})
+	}
+	return map[string]interface{}{
+		"contents": map[string]interface{}{
+			"playlistVideoListRenderer": map[string]interface{}{"contents": items},
+		},
+	}
+}
+
+func TestListPlaylistVideos(t *testing.T) {
+	fetcher := &fixtures.MockHTMLFetcher{}
+	fetcher.On("FetchInnertubeEndpoint", mock.Anything, "browse", map[string]interface{}{"browseId": "VLPL123"}).
+		Return(playlistPage("page2", "video1", "video2"), nil)
+	fetcher.On("FetchInnertubeEndpoint", mock.Anything, "browse", map[string]interface{}{"continuation": "page2"}).
+		Return(playlistPage("", "video2", "video3"), nil)
+
+	service := NewTranscriptService(fetcher)
+	videoIDs, err := service.ListPlaylistVideos(context.Background(), "https://www.youtube.com/playlist?list=PL123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"video1", "video2", "video3"}, videoIDs)
+	fetcher.AssertExpectations(t)
+}
+
+func TestListPlaylistVideosEmpty(t *testing.T) {
+	fetcher := &fixtures.MockHTMLFetcher{}
+	fetcher.On("FetchInnertubeEndpoint", mock.Anything, "browse", map[string]interface{}{"browseId": "VLPL123"}).
+		Return(map[string]interface{}{"alerts": []interface{}{}}, nil)
+
+	service := NewTranscriptService(fetcher)
+	_, err := service.ListPlaylistVideos(context.Background(), "PL123")
+
+	assert.ErrorIs(t, err, yt_errors.ErrPlaylistUnavailable)
+}
+
+func TestListChannelVideos(t *testing.T) {
+	const channelID = "UCabcdefghijklmnopqrstuv"
+
+	t.Run("Handle is resolved", func(t *testing.T) {
+		fetcher := &fixtures.MockHTMLFetcher{}
+		fetcher.On("FetchInnertubeEndpoint", mock.Anything, "navigation/resolve_url", map[string]interface{}{"url": "https://www.youtube.com/@golang"}).
+			Return(map[string]interface{}{
+				"endpoint": map[string]interface{}{
+					"browseEndpoint": map[string]interface{}{"browseId": channelID},
+				},
+			}, nil)
+		fetcher.On("FetchInnertubeEndpoint", mock.Anything, "browse", map[string]interface{}{"browseId": "VLUUabcdefghijklmnopqrstuv"}).
+			Return(playlistPage("", "video1"), nil)
+
+		service := NewTranscriptService(fetcher)
+		videoIDs, err := service.ListChannelVideos(context.Background(), "@golang")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"video1"}, videoIDs)
+		fetcher.AssertExpectations(t)
+	})
+
+	for _, channel := range []string{
+		"youtube.com/@golang",
+		"www.youtube.com/@golang",
+		"m.youtube.com/@golang",
+		"https://m.youtube.com/@golang/",
+		"https://www.youtube.com/@golang",
+	} {
+		t.Run("Handle URL "+channel, func(t *testing.T) {
+			fetcher := &fixtures.MockHTMLFetcher{}
+			fetcher.On("FetchInnertubeEndpoint", mock.Anything, "navigation/resolve_url", map[string]interface{}{"url": "https://www.youtube.com/@golang"}).
+				Return(map[string]interface{}{
+					"endpoint": map[string]interface{}{
+						"browseEndpoint": map[string]interface{}{"browseId": channelID},
+					},
+				}, nil)
+			fetcher.On("FetchInnertubeEndpoint", mock.Anything, "browse", map[string]interface{}{"browseId": "VLUUabcdefghijklmnopqrstuv"}).
+				Return(playlistPage("", "video1"), nil)
+
+			service := NewTranscriptService(fetcher)
+			videoIDs, err := service.ListChannelVideos(context.Background(), channel)
+
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"video1"}, videoIDs)
+			fetcher.AssertExpectations(t)
+		})
+	}
+
+	t.Run("Channel URL is used directly", func(t *testing.T) {
+		fetcher := &fixtures.MockHTMLFetcher{}
+		fetcher.On("FetchInnertubeEndpoint", mock.Anything, "browse", map[string]interface{}{"browseId": "VLUUabcdefghijklmnopqrstuv"}).
+			Return(playlistPage("", "video1"), nil)
+
+		service := NewTranscriptService(fetcher)
+		videoIDs, err := service.ListChannelVideos(context.Background(), "https://www.youtube.com/channel/"+channelID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"video1"}, videoIDs)
+		fetcher.AssertExpectations(t)
+	})
+
+	t.Run("Unknown handle", func(t *testing.T) {
+		fetcher := &fixtures.MockHTMLFetcher{}
+		fetcher.On("FetchInnertubeEndpoint", mock.Anything, "navigation/resolve_url", map[string]interface{}{"url": "https://www.youtube.com/@missing"}).
+			Return(map[string]interface{}{}, nil)
+
+		service := NewTranscriptService(fetcher)
+		_, err := service.ListChannelVideos(context.Background(), "missing")
+
+		assert.ErrorIs(t, err, yt_errors.ErrChannelNotFound)
+	})
+}
@@ -1,14 +1,22 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"iter"
+	"log/slog"
 	"net/url"
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/horiagug/youtube-transcript-api-go/internal/repository"
+	yt_errors "github.com/horiagug/youtube-transcript-api-go/pkg/errors"
+	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_cache"
 	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_models"
 	"golang.org/x/net/html"
 )
@@ -16,10 +24,47 @@ import (
 type TranscriptService interface {
 	GetTranscripts(videoID string, langauges []string, preserve_formatting bool) ([]yt_transcript_models.Transcript, error)
 	GetTranscriptsWithContext(ctx context.Context, videoID string, langauges []string, preserve_formatting bool) ([]yt_transcript_models.Transcript, error)
+	ListTranscripts(ctx context.Context, videoID string) (*yt_transcript_models.TranscriptList, error)
+	GetTranslatedTranscriptsWithContext(ctx context.Context, videoID string, languages []string, targetLanguage string, preserve_formatting bool) ([]yt_transcript_models.Transcript, error)
+	ListPlaylistVideos(ctx context.Context, playlist string) ([]string, error)
+	ListChannelVideos(ctx context.Context, channel string) ([]string, error)
+	GetVideoMetadata(ctx context.Context, videoID string) (*yt_transcript_models.VideoMetadata, error)
+	TranscriptLines(ctx context.Context, videoID string, languages []string, targetLanguage string, preserve_formatting bool) iter.Seq2[yt_transcript_models.VideoLine, error]
 }
 
 type transcriptService struct {
-	fetcher repository.HTMLFetcherType
+	fetcher          repository.HTMLFetcherType
+	trackSelection   TrackSelection
+	languageFallback bool
+	cache            yt_transcript_cache.Cache
+	trackListTTL     time.Duration
+	captionTTL       time.Duration
+	captionFormat    repository.CaptionFormat
+	spans            bool
+	logger           *slog.Logger
+}
+
+// TrackSelection controls which kind of caption track is used when a language
+// has both manually created and auto-generated tracks.
+type TrackSelection int
+
+const (
+	// AnyTrack uses every matching track, manual and generated alike.
+	AnyTrack TrackSelection = iota
+	// ManualOnly ignores auto-generated tracks.
+	ManualOnly
+	// GeneratedOnly ignores manually created tracks.
+	GeneratedOnly
+	// PreferManual uses the manual track of a language and falls back to the generated one.
+	PreferManual
+)
+
+type Option func(*transcriptService)
+
+func WithTrackSelection(selection TrackSelection) Option {
+	return func(t *transcriptService) {
+		t.trackSelection = selection
+	}
 }
 
 type transcriptResult struct {
@@ -27,37 +72,210 @@ type transcriptResult struct {
 	err        error
 }
 
-func NewTranscriptService(fetcher repository.HTMLFetcherType) *transcriptService {
-	return &transcriptService{
-		fetcher: fetcher,
+// WithCaptionFormat sets the format caption tracks are downloaded in. srv3 and json3 give
+// the timing of every word of auto-generated tracks.
+func WithCaptionFormat(format repository.CaptionFormat) Option {
+	return func(t *transcriptService) {
+		t.captionFormat = format
+	}
+}
+
+// WithSpans splits the text of every transcript line into spans of bold, italic and
+// underlined text, independently of whether the formatting tags are preserved in the text.
+func WithSpans(enabled bool) Option {
+	return func(t *transcriptService) {
+		t.spans = enabled
 	}
 }
 
+// WithLogger logs cache hits, caption fetches and failures to logger. Nothing is logged by default.
+func WithLogger(logger *slog.Logger) Option {
+	return func(t *transcriptService) {
+		if logger != nil {
+			t.logger = logger
+		}
+	}
+}
+
+// WithLanguageFallback treats the requested languages as a priority list and only
+// fetches the best track of the first language that is available, manual over generated.
+func WithLanguageFallback(enabled bool) Option {
+	return func(t *transcriptService) {
+		t.languageFallback = enabled
+	}
+}
+
+func NewTranscriptService(fetcher repository.HTMLFetcherType, options ...Option) *transcriptService {
+	t := &transcriptService{
+		fetcher:       fetcher,
+		trackListTTL:  defaultTrackListTTL,
+		captionTTL:    defaultCaptionTTL,
+		captionFormat: repository.CaptionFormatXML,
+		logger:        repository.DiscardLogger,
+	}
+
+	for _, opt := range options {
+		opt(t)
+	}
+
+	return t
+}
+
 func (t transcriptService) GetTranscripts(videoID string, languages []string, preserve_formatting bool) ([]yt_transcript_models.Transcript, error) {
 	return t.GetTranscriptsWithContext(context.Background(), videoID, languages, preserve_formatting)
 }
 
 func (t transcriptService) GetTranscriptsWithContext(ctx context.Context, videoID string, languages []string, preserve_formatting bool) ([]yt_transcript_models.Transcript, error) {
-	videoID = sanitizeVideoId(videoID)
+	videoID = t.normalizeVideoID(videoID)
+	if videoID == "" {
+		return []yt_transcript_models.Transcript{}, yt_errors.ErrInvalidVideoID
+	}
+
+	trascript_data, transcripts, err := t.matchingTracks(ctx, videoID, languages)
+	if err != nil {
+		return []yt_transcript_models.Transcript{}, err
+	}
+
+	return t.processCaptionTracksWithContext(ctx, videoID, transcripts, trascript_data, preserve_formatting)
+}
+
+// matchingTracks fetches the track list of videoID and picks the tracks matching languages.
+func (t transcriptService) matchingTracks(ctx context.Context, videoID string, languages []string) (*yt_transcript_models.VideoTranscriptData, []yt_transcript_models.CaptionTrack, error) {
+	transcript_data, err := t.extractTranscriptList(ctx, videoID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to extract list of transcripts: %w", err)
+	}
+
+	tracks, err := t.getTranscriptsForLanguage(languages, *transcript_data.Transcripts)
+	if err != nil {
+		var notFound *yt_errors.NoTranscriptFoundError
+		if errors.As(err, &notFound) {
+			notFound.VideoID = videoID
+		}
+		return nil, nil, fmt.Errorf("failed to get transcript: %w", err)
+	}
 
-	trascript_data, err := t.extractTranscriptList(ctx, videoID)
+	return transcript_data, tracks, nil
+}
+
+// GetTranslatedTranscriptsWithContext fetches the first translatable track matching languages,
+// machine translated by YouTube into targetLanguage.
+func (t transcriptService) GetTranslatedTranscriptsWithContext(ctx context.Context, videoID string, languages []string, targetLanguage string, preserve_formatting bool) ([]yt_transcript_models.Transcript, error) {
+	videoID = t.normalizeVideoID(videoID)
+	if videoID == "" {
+		return []yt_transcript_models.Transcript{}, yt_errors.ErrInvalidVideoID
+	}
+
+	transcript_data, tracks, err := t.matchingTracks(ctx, videoID, languages)
 	if err != nil {
-		return []yt_transcript_models.Transcript{}, fmt.Errorf("failed to extract list of transcripts: %w", err)
+		return []yt_transcript_models.Transcript{}, err
 	}
 
-	transcripts, err := t.getTranscriptsForLanguage(languages, *trascript_data.Transcripts)
+	translated, err := translateCaptionTrack(videoID, tracks, transcript_data.Transcripts.TranslationLanguages, targetLanguage)
 	if err != nil {
-		return []yt_transcript_models.Transcript{}, fmt.Errorf("failed to get transcript: %w", err)
+		return []yt_transcript_models.Transcript{}, err
+	}
+
+	return t.processCaptionTracksWithContext(ctx, videoID, []yt_transcript_models.CaptionTrack{translated}, transcript_data, preserve_formatting)
+}
+
+// translateCaptionTrack picks the first translatable track and points it at its translation
+// into targetLanguage, validating the target against the languages YouTube offers.
+func translateCaptionTrack(videoID string, tracks []yt_transcript_models.CaptionTrack, translationLanguages *[]yt_transcript_models.LanguageData, targetLanguage string) (yt_transcript_models.CaptionTrack, error) {
+	var source *yt_transcript_models.CaptionTrack
+	for i := range tracks {
+		if tracks[i].IsTranslatable {
+			source = &tracks[i]
+			break
+		}
 	}
+	if source == nil {
+		return yt_transcript_models.CaptionTrack{}, &yt_errors.NotTranslatableError{VideoID: videoID, LanguageCode: tracks[0].LanguageCode}
+	}
+
+	var target *yt_transcript_models.LanguageData
+	var available []string
+	if translationLanguages != nil {
+		available = make([]string, 0, len(*translationLanguages))
+		for i, lang := range *translationLanguages {
+			if lang.LanguageCode == targetLanguage {
+				target = &(*translationLanguages)[i]
+			}
+			available = append(available, lang.LanguageCode)
+		}
+	}
+	if target == nil {
+		return yt_transcript_models.CaptionTrack{}, &yt_errors.TranslationLanguageNotAvailableError{
+			VideoID:      videoID,
+			LanguageCode: targetLanguage,
+			Available:    available,
+		}
+	}
+
+	u, err := url.Parse(source.BaseUrl)
+	if err != nil {
+		return yt_transcript_models.CaptionTrack{}, &yt_errors.ParseError{What: "caption track URL", Err: err}
+	}
+	query := u.Query()
+	query.Set("tlang", target.LanguageCode)
+	u.RawQuery = query.Encode()
+
+	return yt_transcript_models.CaptionTrack{
+		Kind:         source.Kind,
+		LanguageCode: target.LanguageCode,
+		BaseUrl:      u.String(),
+		Name:         target.Language,
+	}, nil
+}
+
+func (t transcriptService) ListTranscripts(ctx context.Context, videoID string) (*yt_transcript_models.TranscriptList, error) {
+	videoID = t.normalizeVideoID(videoID)
+	if videoID == "" {
+		return nil, yt_errors.ErrInvalidVideoID
+	}
+
+	transcript_data, err := t.extractTranscriptList(ctx, videoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract list of transcripts: %w", err)
+	}
+
+	var translationLanguages []yt_transcript_models.LanguageData
+	if transcript_data.Transcripts.TranslationLanguages != nil {
+		translationLanguages = *transcript_data.Transcripts.TranslationLanguages
+	}
+
+	available := make([]yt_transcript_models.AvailableTranscript, 0, len(transcript_data.Transcripts.CaptionTracks))
+	for _, track := range transcript_data.Transcripts.CaptionTracks {
+		info := yt_transcript_models.AvailableTranscript{
+			Language:       track.Name.SimpleText,
+			LanguageCode:   track.LanguageCode,
+			IsGenerated:    isGenerated(track),
+			IsTranslatable: track.IsTranslatable,
+		}
+		if track.IsTranslatable {
+			info.TranslationLanguages = translationLanguages
+		}
+		available = append(available, info)
+	}
+
+	return &yt_transcript_models.TranscriptList{
+		VideoID:       videoID,
+		CaptionTracks: transcript_data.Transcripts.CaptionTracks,
+		VideoTitle:    transcript_data.Title,
+		Transcripts:   available,
+		Metadata:      transcript_data.Metadata,
+	}, nil
+}
 
-	return t.processCaptionTracksWithContext(ctx, videoID, transcripts, trascript_data.Title, preserve_formatting)
+func isGenerated(track yt_transcript_models.CaptionTrack) bool {
+	return track.Kind != nil && *track.Kind == "asr"
 }
 
 func (t *transcriptService) processCaptionTracks(video_id string, captionTracks []yt_transcript_models.CaptionTrack, title string, preserve_formatting bool) ([]yt_transcript_models.Transcript, error) {
-	return t.processCaptionTracksWithContext(context.Background(), video_id, captionTracks, title, preserve_formatting)
+	return t.processCaptionTracksWithContext(context.Background(), video_id, captionTracks, &yt_transcript_models.VideoTranscriptData{Title: title}, preserve_formatting)
 }
 
-func (t *transcriptService) processCaptionTracksWithContext(ctx context.Context, video_id string, captionTracks []yt_transcript_models.CaptionTrack, title string, preserve_formatting bool) ([]yt_transcript_models.Transcript, error) {
+func (t *transcriptService) processCaptionTracksWithContext(ctx context.Context, video_id string, captionTracks []yt_transcript_models.CaptionTrack, transcriptData *yt_transcript_models.VideoTranscriptData, preserve_formatting bool) ([]yt_transcript_models.Transcript, error) {
 	resultChan := make(chan transcriptResult, len(captionTracks))
 	var wg sync.WaitGroup
 
@@ -69,25 +287,22 @@ func (t *transcriptService) processCaptionTracksWithContext(ctx context.Context,
 		go func(tr yt_transcript_models.CaptionTrack) {
 			defer wg.Done()
 
-			is_generated := false
-			if tr.Kind != nil && *tr.Kind == "asr" {
-				is_generated = true
-			}
-
-			lines, err := t.getTranscriptFromTrackWithContext(ctx, tr, preserve_formatting)
+			lines, err := t.getTranscriptFromTrackWithContext(ctx, video_id, tr, preserve_formatting)
 			if err != nil {
+				t.logger.Debug("failed to get caption track", "video_id", video_id, "language", tr.LanguageCode, "error", err)
 				resultChan <- transcriptResult{err: fmt.Errorf("error getting transcript from track: %w", err)}
 				return
 			}
 
 			result := yt_transcript_models.Transcript{
 				VideoID:        video_id,
-				VideoTitle:     title,
+				VideoTitle:     transcriptData.Title,
 				Language:       tr.Name.SimpleText,
 				LanguageCode:   tr.LanguageCode,
-				IsGenerated:    is_generated,
+				IsGenerated:    isGenerated(tr),
 				IsTranslatable: tr.IsTranslatable,
 				Lines:          lines,
+				Metadata:       transcriptData.Metadata,
 			}
 
 			resultChan <- transcriptResult{transcript: result}
@@ -101,7 +316,6 @@ func (t *transcriptService) processCaptionTracksWithContext(ctx context.Context,
 
 	for result := range resultChan {
 		if result.err != nil {
-			fmt.Printf("Error processing transcript: %v\n", result.err)
 			return results, result.err
 		}
 		results = append(results, result.transcript)
@@ -122,16 +336,23 @@ func extractInnerTubeApiKey(htmlContent string) string {
 	return ""
 }
 
+// extractInnertubeVideoDetails reads the playability status, metadata and caption tracks of a player
+// response. The caption renderer is nil when the response has no captions section or renderer.
 func extractInnertubeVideoDetails(data map[string]interface{}) (*yt_transcript_models.InnertubeData, error) {
-	// Extract captions section directly
+	details := &yt_transcript_models.InnertubeData{
+		PlayabilityStatus: extractPlayabilityStatus(data),
+		Metadata:          extractVideoMetadata(data),
+	}
+
+	// Playable videos without captions have no captions section, a null one or one without renderer
 	captions, ok := data["captions"].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("captions not found in response")
+	if !ok || captions["playerCaptionsTracklistRenderer"] == nil {
+		return details, nil
 	}
 
 	renderer, ok := captions["playerCaptionsTracklistRenderer"].(map[string]interface{})
 	if !ok {
-		return nil, fmt.Errorf("playerCaptionsTracklistRenderer not found")
+		return nil, &yt_errors.ParseError{What: "innertube captions", Err: fmt.Errorf("malformed playerCaptionsTracklistRenderer")}
 	}
 
 	// Extract caption tracks
@@ -200,17 +421,13 @@ func extractInnertubeVideoDetails(data map[string]interface{}) (*yt_transcript_m
 		TranslationLanguages: translationLanguages,
 	}
 
-	return &yt_transcript_models.InnertubeData{
-		Captions: yt_transcript_models.CaptionsDetails{
-			PlayerCaptionsTracklistRenderer: transcriptData,
-		},
-	}, nil
+	details.Captions.PlayerCaptionsTracklistRenderer = transcriptData
+	return details, nil
 }
 
 func extractTitle(htmlContent string) string {
 	doc, err := html.Parse(strings.NewReader(htmlContent))
 	if err != nil {
-		fmt.Printf("Error fetching the title")
 		return ""
 	}
 
@@ -232,10 +449,12 @@ func extractTitle(htmlContent string) string {
 	return title
 }
 
-func (t *transcriptService) extractTranscriptList(ctx context.Context, video_id string) (*yt_transcript_models.VideoTranscriptData, error) {
+// fetchPlayerResponse fetches the watch page of the video for its title and innertube API key,
+// then the player response.
+func (t *transcriptService) fetchPlayerResponse(ctx context.Context, video_id string) (string, map[string]interface{}, error) {
 	html, err := t.fetcher.FetchVideo(video_id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch video page: %w", err)
+		return "", nil, fmt.Errorf("failed to fetch video page: %w", err)
 	}
 
 	body := string(html)
@@ -246,7 +465,21 @@ func (t *transcriptService) extractTranscriptList(ctx context.Context, video_id
 
 	innertube_data, err := t.fetcher.FetchInnertubeData(ctx, video_id, innertube_api_key, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch video page: %w", err)
+		return "", nil, fmt.Errorf("failed to fetch video page: %w", err)
+	}
+
+	return title, innertube_data, nil
+}
+
+func (t *transcriptService) extractTranscriptList(ctx context.Context, video_id string) (*yt_transcript_models.VideoTranscriptData, error) {
+	if cached, ok := t.cachedTrackList(video_id); ok {
+		t.logger.Debug("track list cache hit", "video_id", video_id)
+		return cached, nil
+	}
+
+	title, innertube_data, err := t.fetchPlayerResponse(ctx, video_id)
+	if err != nil {
+		return nil, err
 	}
 
 	// Directly extract data without unnecessary marshal/unmarshal
@@ -255,73 +488,244 @@ func (t *transcriptService) extractTranscriptList(ctx context.Context, video_id
 		return nil, fmt.Errorf("failed to extract video details: %w", err)
 	}
 
-	if videoDetails.Captions.PlayerCaptionsTracklistRenderer == nil {
-		return nil, fmt.Errorf("playerCaptionsTracklistRenderer not found")
+	if err := playabilityError(video_id, videoDetails.PlayabilityStatus); err != nil {
+		t.logger.Debug("video is not playable", "video_id", video_id, "status", videoDetails.PlayabilityStatus.Status, "error", err)
+		return nil, err
+	}
+
+	if videoDetails.Captions.PlayerCaptionsTracklistRenderer == nil || len(videoDetails.Captions.PlayerCaptionsTracklistRenderer.CaptionTracks) == 0 {
+		// Live streams only get captions once they have ended
+		if videoDetails.Metadata != nil && videoDetails.Metadata.IsLive {
+			return nil, &yt_errors.LiveStreamError{VideoID: video_id}
+		}
+		return nil, &yt_errors.TranscriptsDisabledError{VideoID: video_id}
 	}
 
 	transcripts := videoDetails.Captions.PlayerCaptionsTracklistRenderer
 
-	return &yt_transcript_models.VideoTranscriptData{Transcripts: transcripts, Title: title}, nil
+	transcriptData := &yt_transcript_models.VideoTranscriptData{
+		Transcripts: transcripts,
+		Title:       videoTitle(videoDetails.Metadata, title),
+		Metadata:    videoDetails.Metadata,
+	}
+	t.cacheTrackList(video_id, transcriptData)
+
+	return transcriptData, nil
 }
 
 func (s transcriptService) getTranscriptsForLanguage(languages []string, transcripts yt_transcript_models.TranscriptData) ([]yt_transcript_models.CaptionTrack, error) {
 	if len(languages) == 0 {
-		return transcripts.CaptionTracks, nil
+		caption_tracks := s.selectTracks(transcripts.CaptionTracks)
+		if len(caption_tracks) == 0 {
+			return []yt_transcript_models.CaptionTrack{}, &yt_errors.NoTranscriptFoundError{
+				Available: availableLanguages(transcripts.CaptionTracks),
+			}
+		}
+		return caption_tracks, nil
 	}
 
 	// Pre-allocate with capacity hint based on language count
 	caption_tracks := make([]yt_transcript_models.CaptionTrack, 0, len(languages))
 
+	seen := make(map[yt_transcript_models.CaptionTrack]bool, len(transcripts.CaptionTracks))
+
 	for _, lang := range languages {
-		for _, track := range transcripts.CaptionTracks {
-			if track.LanguageCode == lang {
+		selected := s.selectTracks(tracksForLanguage(lang, transcripts.CaptionTracks))
+		if s.languageFallback && len(selected) > 0 {
+			return []yt_transcript_models.CaptionTrack{bestTrack(selected)}, nil
+		}
+
+		for _, track := range selected {
+			// Several requested tags such as "en" and "en-US" can match the same track
+			if !seen[track] {
+				seen[track] = true
 				caption_tracks = append(caption_tracks, track)
 			}
 		}
 	}
 
 	if len(caption_tracks) == 0 {
-		return []yt_transcript_models.CaptionTrack{}, fmt.Errorf("no transcript found for languages %s", languages)
+		return []yt_transcript_models.CaptionTrack{}, &yt_errors.NoTranscriptFoundError{
+			RequestedLanguages: languages,
+			Available:          availableLanguages(transcripts.CaptionTracks),
+		}
 	}
 
 	return caption_tracks, nil
 }
 
-func (s transcriptService) getTranscriptFromTrackWithContext(ctx context.Context, track yt_transcript_models.CaptionTrack, preserve_formatting bool) ([]yt_transcript_models.TranscriptLine, error) {
-	url := strings.Replace(track.BaseUrl, "&fmt=srv3", "", -1)
-	body, err := s.fetcher.FetchWithContext(ctx, url, nil)
-	if err != nil {
-		return []yt_transcript_models.TranscriptLine{}, fmt.Errorf("failed to fetch transcript: %w", err)
+// selectTracks applies the track selection policy to tracks.
+func (s transcriptService) selectTracks(tracks []yt_transcript_models.CaptionTrack) []yt_transcript_models.CaptionTrack {
+	switch s.trackSelection {
+	case ManualOnly:
+		return filterTracks(tracks, func(track yt_transcript_models.CaptionTrack) bool { return !isGenerated(track) })
+	case GeneratedOnly:
+		return filterTracks(tracks, isGenerated)
+	case PreferManual:
+		hasManual := make(map[string]bool, len(tracks))
+		for _, track := range tracks {
+			if !isGenerated(track) {
+				hasManual[track.LanguageCode] = true
+			}
+		}
+		return filterTracks(tracks, func(track yt_transcript_models.CaptionTrack) bool {
+			return !isGenerated(track) || !hasManual[track.LanguageCode]
+		})
+	default:
+		return tracks
 	}
+}
 
-	parser := repository.NewTranscriptParser(preserve_formatting)
+func filterTracks(tracks []yt_transcript_models.CaptionTrack, keep func(yt_transcript_models.CaptionTrack) bool) []yt_transcript_models.CaptionTrack {
+	filtered := make([]yt_transcript_models.CaptionTrack, 0, len(tracks))
+	for _, track := range tracks {
+		if keep(track) {
+			filtered = append(filtered, track)
+		}
+	}
+	return filtered
+}
 
-	transcript, err := parser.Parse(string(body))
+func availableLanguages(tracks []yt_transcript_models.CaptionTrack) []string {
+	available := make([]string, 0, len(tracks))
+	for _, track := range tracks {
+		available = append(available, track.LanguageCode)
+	}
+	return available
+}
+
+func (s transcriptService) getTranscriptFromTrackWithContext(ctx context.Context, videoID string, track yt_transcript_models.CaptionTrack, preserve_formatting bool) ([]yt_transcript_models.TranscriptLine, error) {
+	var transcript []yt_transcript_models.TranscriptLine
+	err := s.streamTrackLines(ctx, videoID, track, preserve_formatting, func(line yt_transcript_models.TranscriptLine) bool {
+		transcript = append(transcript, line)
+		return true
+	})
 	if err != nil {
-		return []yt_transcript_models.TranscriptLine{}, fmt.Errorf("failed to parse transcript: %w", err)
+		return []yt_transcript_models.TranscriptLine{}, err
 	}
 	return transcript, nil
 }
 
+// streamTrackLines passes the lines of a caption track to yield while it is downloaded, when
+// the fetcher can stream it, until yield returns false. Lines yielded before the download was
+// retried are skipped the second time.
+func (s transcriptService) streamTrackLines(ctx context.Context, videoID string, track yt_transcript_models.CaptionTrack, preserve_formatting bool, yield func(yt_transcript_models.TranscriptLine) bool) error {
+	cacheKey := captionCacheKey(videoID, track, s.captionFormat)
+	parser := repository.NewTranscriptParser(preserve_formatting, repository.WithSpans(s.spans))
+
+	yielded, complete := 0, true
+	yieldLines := func(r io.Reader) error {
+		index := 0
+		for line, err := range parser.Lines(r) {
+			if err != nil {
+				return &yt_errors.ParseError{What: "transcript", Err: err}
+			}
+			if index++; index <= yielded {
+				continue
+			}
+			yielded++
+			if !yield(line) {
+				complete = false
+				return nil
+			}
+		}
+		return nil
+	}
+
+	if s.cache != nil {
+		if body, ok := s.cache.Get(cacheKey); ok {
+			s.logger.Debug("caption cache hit", "video_id", videoID, "key", cacheKey)
+			return yieldLines(bytes.NewReader(body))
+		}
+	}
+
+	s.logger.Debug("fetching caption track", "video_id", videoID, "language", track.LanguageCode, "generated", isGenerated(track))
+	url := captionURL(track.BaseUrl, s.captionFormat)
+
+	var body []byte
+	if streamer, ok := s.fetcher.(repository.StreamingFetcher); ok {
+		var buffer bytes.Buffer
+		err := streamer.FetchStreamWithContext(ctx, url, nil, func(r io.Reader) error {
+			// Retried downloads are consumed again from the start
+			buffer.Reset()
+			if s.cache != nil {
+				r = io.TeeReader(r, &buffer)
+			}
+			return yieldLines(r)
+		})
+		if err != nil {
+			var parseErr *yt_errors.ParseError
+			if errors.As(err, &parseErr) {
+				return err
+			}
+			return fmt.Errorf("failed to fetch transcript: %w", err)
+		}
+		body = buffer.Bytes()
+	} else {
+		var err error
+		body, err = s.fetcher.FetchWithContext(ctx, url, nil)
+		if err != nil {
+			return fmt.Errorf("failed to fetch transcript: %w", err)
+		}
+		if err := yieldLines(bytes.NewReader(body)); err != nil {
+			return err
+		}
+	}
+
+	// Only complete bodies that parsed are cached
+	if s.cache != nil && complete {
+		if err := s.cache.Set(cacheKey, body, s.captionTTL); err != nil {
+			s.logger.Warn("failed to cache captions", "video_id", videoID, "key", cacheKey, "error", err)
+		}
+	}
+	return nil
+}
+
+// normalizeVideoID sanitizes videoID and warns about URLs it can't take an ID from.
+func (t transcriptService) normalizeVideoID(videoID string) string {
+	sanitized := sanitizeVideoId(videoID)
+	if sanitized == videoID && isURL(videoID) {
+		t.logger.Warn("input doesn't look like a YouTube video URL, using it as the video ID", "input", videoID)
+	}
+	return sanitized
+}
+
+func isURL(videoID string) bool {
+	return strings.HasPrefix(videoID, "http://") || strings.HasPrefix(videoID, "https://") || strings.HasPrefix(videoID, "www.")
+}
+
+// captionURL points a caption track URL at format, the track list asks for srv3 by default.
+func captionURL(baseURL string, format repository.CaptionFormat) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return baseURL
+	}
+
+	query := u.Query()
+	query.Del("fmt")
+	if format != repository.CaptionFormatXML {
+		query.Set("fmt", string(format))
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
 func sanitizeVideoId(videoID string) string {
-	if strings.HasPrefix(videoID, "http://") || strings.HasPrefix(videoID, "https://") || strings.HasPrefix(videoID, "www.") {
+	if isURL(videoID) {
 		if strings.Contains(videoID, "youtube.com") {
 			u, err := url.Parse(videoID)
 			if err != nil {
-				fmt.Println("Error parsing URL")
 				return videoID
 			}
 			return u.Query().Get("v")
 		} else if strings.Contains(videoID, "youtu.be") {
 			u, err := url.Parse(videoID)
 			if err != nil {
-				fmt.Println("Error parsing URL")
 				return videoID
 			}
 			// For youtu.be, the video ID is in the path
 			return strings.TrimPrefix(u.Path, "/")
 		}
-		fmt.Println("Warning: this doesn't look like a youtube video, we'll still try to process it.")
 	}
 	return videoID
 }
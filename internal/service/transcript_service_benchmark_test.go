@@ -1,6 +1,15 @@
 package service
 
 import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
 	"testing"
 
 	"github.com/horiagug/youtube-transcript-api-go/internal/repository"
@@ -94,3 +103,113 @@ func BenchmarkExtractInnertubeVideoDetails(b *testing.B) {
 	}
 }
 
+// longTranscript generates a legacy XML caption track of a livestream lasting hours.
+func longTranscript(hours int) []byte {
+	var body bytes.Buffer
+	body.WriteString(`<?xml version="1.0" encoding="utf-8" ?><transcript>`)
+	for start := 0; start < hours*3600; start += 3 {
+		fmt.Fprintf(&body, `<text start="%d" dur="3">line said at &lt;b&gt;%d&lt;/b&gt; seconds into the stream</text>`, start, start)
+	}
+	body.WriteString(`</transcript>`)
+	return body.Bytes()
+}
+
+var tagRegex = regexp.MustCompile(`(?i)<[^>]*>`)
+
+// unmarshalTranscript is the parser caption tracks went through before they were streamed,
+// decoding the whole body with xml.Unmarshal. It is only kept to compare allocations.
+func unmarshalTranscript(body []byte) ([]yt_transcript_models.TranscriptLine, error) {
+	var parsed struct {
+		XMLName xml.Name `xml:"transcript"`
+		Texts   []struct {
+			Text     string `xml:",chardata"`
+			Start    string `xml:"start,attr"`
+			Duration string `xml:"dur,attr"`
+		} `xml:"text"`
+	}
+	if err := xml.Unmarshal(body, &parsed); err != nil {
+		return nil, err
+	}
+
+	var lines []yt_transcript_models.TranscriptLine
+	for _, entry := range parsed.Texts {
+		start, _ := strconv.ParseFloat(entry.Start, 64)
+		duration, _ := strconv.ParseFloat(entry.Duration, 64)
+		lines = append(lines, yt_transcript_models.TranscriptLine{
+			Text:     html.UnescapeString(tagRegex.ReplaceAllString(entry.Text, "")),
+			Start:    start,
+			Duration: duration,
+		})
+	}
+	return lines, nil
+}
+
+// bufferedFetcher hides the streaming support of the fetcher it wraps.
+type bufferedFetcher struct {
+	repository.HTMLFetcherType
+}
+
+func BenchmarkParseLongTranscript(b *testing.B) {
+	body := longTranscript(4)
+	parser := repository.NewTranscriptParser(false)
+
+	b.Run("unmarshal", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := unmarshalTranscript(body); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("string", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := parser.Parse(string(body)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("lines", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, err := range parser.Lines(bytes.NewReader(body)) {
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
+
+func BenchmarkFetchLongTranscript(b *testing.B) {
+	body := longTranscript(4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	track := yt_transcript_models.CaptionTrack{BaseUrl: server.URL, LanguageCode: "en"}
+	fetcher := repository.NewHTMLFetcher()
+
+	benchmarks := []struct {
+		name    string
+		fetcher repository.HTMLFetcherType
+	}{
+		{"buffered", bufferedFetcher{fetcher}},
+		{"streamed", fetcher},
+	}
+
+	for _, bm := range benchmarks {
+		service := NewTranscriptService(bm.fetcher)
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := service.getTranscriptFromTrackWithContext(context.Background(), "test123", track, false); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
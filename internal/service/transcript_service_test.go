@@ -1,13 +1,23 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"github.com/horiagug/youtube-transcript-api-go/internal/repository"
 	"github.com/horiagug/youtube-transcript-api-go/internal/repository/fixtures"
+	yt_errors "github.com/horiagug/youtube-transcript-api-go/pkg/errors"
+	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_cache"
 	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_models"
 )
 
@@ -46,7 +56,7 @@ func TestGetTranscripts(t *testing.T) {
 					VideoTitle:     "Test Video",
 					Language:       "English",
 					LanguageCode:   "en",
-					IsGenerated:    false,
+					IsGenerated:    true,
 					IsTranslatable: true,
 					Lines: []yt_transcript_models.TranscriptLine{
 						{
@@ -120,6 +130,655 @@ func TestGetTranscripts(t *testing.T) {
 	}
 }
 
+func TestGetTranscriptsTypedErrors(t *testing.T) {
+	englishTrack := map[string]interface{}{
+		"baseUrl":      "http://example.com/transcript",
+		"name":         map[string]interface{}{"simpleText": "English"},
+		"languageCode": "en",
+	}
+
+	tests := []struct {
+		name          string
+		innertubeData map[string]interface{}
+		languages     []string
+		expectedError error
+		check         func(t *testing.T, err error)
+	}{
+		{
+			name: "No transcript for requested languages",
+			innertubeData: map[string]interface{}{
+				"captions": map[string]interface{}{
+					"playerCaptionsTracklistRenderer": map[string]interface{}{
+						"captionTracks": []interface{}{englishTrack},
+					},
+				},
+			},
+			languages:     []string{"de"},
+			expectedError: yt_errors.ErrNoTranscript,
+			check: func(t *testing.T, err error) {
+				var notFound *yt_errors.NoTranscriptFoundError
+				assert.True(t, errors.As(err, &notFound))
+				assert.Equal(t, "abc123", notFound.VideoID)
+				assert.Equal(t, []string{"de"}, notFound.RequestedLanguages)
+				assert.Equal(t, []string{"en"}, notFound.Available)
+			},
+		},
+		{
+			name:          "Transcripts disabled",
+			innertubeData: map[string]interface{}{"playabilityStatus": map[string]interface{}{"status": "OK"}},
+			languages:     []string{"en"},
+			expectedError: yt_errors.ErrTranscriptsDisabled,
+		},
+		{
+			name: "Transcripts disabled with null captions",
+			innertubeData: map[string]interface{}{
+				"playabilityStatus": map[string]interface{}{"status": "OK"},
+				"captions":          nil,
+			},
+			languages:     []string{"en"},
+			expectedError: yt_errors.ErrTranscriptsDisabled,
+		},
+		{
+			name: "Transcripts disabled without caption renderer",
+			innertubeData: map[string]interface{}{
+				"playabilityStatus": map[string]interface{}{"status": "OK"},
+				"captions":          map[string]interface{}{"playerCaptionsRenderer": map[string]interface{}{}},
+			},
+			languages:     []string{"en"},
+			expectedError: yt_errors.ErrTranscriptsDisabled,
+		},
+		{
+			name: "Age restricted",
+			innertubeData: map[string]interface{}{
+				"playabilityStatus": map[string]interface{}{
+					"status": "LOGIN_REQUIRED",
+					"reason": "Sign in to confirm your age",
+				},
+			},
+			languages:     []string{"en"},
+			expectedError: yt_errors.ErrAgeRestricted,
+		},
+		{
+			name: "Login required",
+			innertubeData: map[string]interface{}{
+				"playabilityStatus": map[string]interface{}{
+					"status": "LOGIN_REQUIRED",
+					"reason": "Join this channel to get access to members-only content",
+				},
+			},
+			languages:     []string{"en"},
+			expectedError: yt_errors.ErrLoginRequired,
+			check: func(t *testing.T, err error) {
+				var loginRequired *yt_errors.LoginRequiredError
+				assert.True(t, errors.As(err, &loginRequired))
+				assert.Equal(t, "abc123", loginRequired.VideoID)
+				assert.Equal(t, "Join this channel to get access to members-only content", loginRequired.Reason)
+			},
+		},
+		{
+			name: "Video unavailable",
+			innertubeData: map[string]interface{}{
+				"playabilityStatus": map[string]interface{}{
+					"status": "ERROR",
+					"reason": "Video unavailable",
+				},
+			},
+			languages:     []string{"en"},
+			expectedError: yt_errors.ErrVideoUnavailable,
+			check: func(t *testing.T, err error) {
+				var unavailable *yt_errors.VideoUnavailableError
+				assert.True(t, errors.As(err, &unavailable))
+				assert.Equal(t, "Video unavailable", unavailable.Reason)
+			},
+		},
+		{
+			name: "Live stream without captions",
+			innertubeData: map[string]interface{}{
+				"playabilityStatus": map[string]interface{}{"status": "OK"},
+				"videoDetails":      map[string]interface{}{"isLive": true},
+			},
+			languages:     []string{"en"},
+			expectedError: yt_errors.ErrLiveStream,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher := &fixtures.MockHTMLFetcher{}
+			fetcher.On("FetchVideo", "abc123").Return([]byte(`<title>Test Video</title>"INNERTUBE_API_KEY":"test_api_key"`), nil)
+			fetcher.On("FetchInnertubeData", "abc123", "test_api_key").Return(tt.innertubeData, nil)
+
+			service := NewTranscriptService(fetcher)
+			_, err := service.GetTranscripts("abc123", tt.languages, false)
+
+			assert.ErrorIs(t, err, tt.expectedError)
+			if tt.check != nil {
+				tt.check(t, err)
+			}
+			fetcher.AssertExpectations(t)
+		})
+	}
+
+	t.Run("Invalid video ID", func(t *testing.T) {
+		service := NewTranscriptService(&fixtures.MockHTMLFetcher{})
+		_, err := service.GetTranscripts("https://www.youtube.com/watch?list=abc", []string{"en"}, false)
+		assert.ErrorIs(t, err, yt_errors.ErrInvalidVideoID)
+	})
+}
+
+func TestPlayabilityError(t *testing.T) {
+	tests := []struct {
+		name          string
+		playability   map[string]interface{}
+		expectedError error
+		expectedType  interface{}
+	}{
+		{
+			name:        "OK",
+			playability: map[string]interface{}{"status": "OK"},
+		},
+		{
+			name:          "Unavailable",
+			playability:   map[string]interface{}{"status": "ERROR", "reason": "Video unavailable"},
+			expectedError: yt_errors.ErrVideoUnavailable,
+			expectedType:  &yt_errors.VideoUnavailableError{},
+		},
+		{
+			name:          "Private",
+			playability:   map[string]interface{}{"status": "LOGIN_REQUIRED", "reason": "This video is private"},
+			expectedError: yt_errors.ErrVideoPrivate,
+			expectedType:  &yt_errors.VideoPrivateError{},
+		},
+		{
+			name: "Removed",
+			playability: map[string]interface{}{
+				"status": "ERROR",
+				"reason": "Video unavailable",
+				"errorScreen": map[string]interface{}{
+					"playerErrorMessageRenderer": map[string]interface{}{
+						"subreason": map[string]interface{}{
+							"runs": []interface{}{
+								map[string]interface{}{"text": "This video has been removed "},
+								map[string]interface{}{"text": "by the uploader"},
+							},
+						},
+					},
+				},
+			},
+			expectedError: yt_errors.ErrVideoRemoved,
+			expectedType:  &yt_errors.VideoRemovedError{},
+		},
+		{
+			name: "Region blocked",
+			playability: map[string]interface{}{
+				"status": "UNPLAYABLE",
+				"errorScreen": map[string]interface{}{
+					"playerErrorMessageRenderer": map[string]interface{}{
+						"reason": map[string]interface{}{
+							"simpleText": "The uploader has not made this video available in your country",
+						},
+					},
+				},
+			},
+			expectedError: yt_errors.ErrRegionBlocked,
+			expectedType:  &yt_errors.RegionBlockedError{},
+		},
+		{
+			name:          "Age restricted",
+			playability:   map[string]interface{}{"status": "LOGIN_REQUIRED", "reason": "Sign in to confirm your age"},
+			expectedError: yt_errors.ErrAgeRestricted,
+			expectedType:  &yt_errors.AgeRestrictedError{},
+		},
+		{
+			name:          "Login required",
+			playability:   map[string]interface{}{"status": "LOGIN_REQUIRED", "reason": "Sign in to confirm you're not a bot"},
+			expectedError: yt_errors.ErrLoginRequired,
+			expectedType:  &yt_errors.LoginRequiredError{},
+		},
+		{
+			name:          "Upcoming live stream",
+			playability:   map[string]interface{}{"status": "LIVE_STREAM_OFFLINE", "reason": "Premieres in 2 hours"},
+			expectedError: yt_errors.ErrLiveStream,
+			expectedType:  &yt_errors.LiveStreamError{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := extractPlayabilityStatus(map[string]interface{}{"playabilityStatus": tt.playability})
+			err := playabilityError("abc123", status)
+
+			if tt.expectedError == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.expectedError)
+			assert.IsType(t, tt.expectedType, err)
+			assert.Contains(t, err.Error(), "abc123")
+		})
+	}
+
+	t.Run("Removed videos are unavailable", func(t *testing.T) {
+		err := playabilityError("abc123", yt_transcript_models.PlayabilityStatus{Status: "ERROR", Reason: "This video has been removed for violating YouTube's Terms of Service"})
+		assert.ErrorIs(t, err, yt_errors.ErrVideoUnavailable)
+	})
+}
+
+func TestListTranscripts(t *testing.T) {
+	fetcher := &fixtures.MockHTMLFetcher{}
+	fetcher.On("FetchVideo", "abc123").Return([]byte(`<title>Test Video</title>"INNERTUBE_API_KEY":"test_api_key"`), nil)
+	fetcher.On("FetchInnertubeData", "abc123", "test_api_key").Return(map[string]interface{}{
+		"captions": map[string]interface{}{
+			"playerCaptionsTracklistRenderer": map[string]interface{}{
+				"captionTracks": []interface{}{
+					map[string]interface{}{
+						"baseUrl":        "http://example.com/en",
+						"name":           map[string]interface{}{"simpleText": "English"},
+						"languageCode":   "en",
+						"isTranslatable": true,
+					},
+					map[string]interface{}{
+						"baseUrl":      "http://example.com/de",
+						"name":         map[string]interface{}{"simpleText": "German (auto-generated)"},
+						"languageCode": "de",
+						"kind":         "asr",
+					},
+				},
+				"translationLanguages": []interface{}{
+					map[string]interface{}{
+						"languageCode": "fr",
+						"languageName": map[string]interface{}{"simpleText": "French"},
+					},
+				},
+			},
+		},
+	}, nil)
+
+	service := NewTranscriptService(fetcher)
+	list, err := service.ListTranscripts(context.Background(), "abc123")
+
+	asr := "asr"
+	assert.NoError(t, err)
+	assert.Equal(t, &yt_transcript_models.TranscriptList{
+		VideoID: "abc123",
+		CaptionTracks: []yt_transcript_models.CaptionTrack{
+			{
+				LanguageCode:   "en",
+				BaseUrl:        "http://example.com/en",
+				Name:           yt_transcript_models.LanguageName{SimpleText: "English"},
+				IsTranslatable: true,
+			},
+			{
+				Kind:         &asr,
+				LanguageCode: "de",
+				BaseUrl:      "http://example.com/de",
+				Name:         yt_transcript_models.LanguageName{SimpleText: "German (auto-generated)"},
+			},
+		},
+		VideoTitle: "Test Video",
+		Transcripts: []yt_transcript_models.AvailableTranscript{
+			{
+				Language:       "English",
+				LanguageCode:   "en",
+				IsTranslatable: true,
+				TranslationLanguages: []yt_transcript_models.LanguageData{
+					{LanguageCode: "fr", Language: yt_transcript_models.LanguageName{SimpleText: "French"}},
+				},
+			},
+			{
+				Language:     "German (auto-generated)",
+				LanguageCode: "de",
+				IsGenerated:  true,
+			},
+		},
+	}, list)
+	// No caption bodies are downloaded
+	fetcher.AssertNotCalled(t, "FetchWithContext", mock.Anything, mock.Anything, mock.Anything)
+	fetcher.AssertExpectations(t)
+}
+
+func TestGetVideoMetadata(t *testing.T) {
+	playerResponse := map[string]interface{}{
+		"playabilityStatus": map[string]interface{}{"status": "OK"},
+		"videoDetails": map[string]interface{}{
+			"videoId":          "abc123",
+			"title":            "Player Title",
+			"channelId":        "UC123",
+			"author":           "Test Channel",
+			"shortDescription": "A description",
+			"lengthSeconds":    "213",
+			"viewCount":        "1500000000",
+			"keywords":         []interface{}{"go", "testing"},
+			"isLiveContent":    false,
+			"thumbnail": map[string]interface{}{
+				"thumbnails": []interface{}{
+					map[string]interface{}{"url": "https://i.ytimg.com/vi/abc123/default.jpg", "width": float64(120), "height": float64(90)},
+				},
+			},
+		},
+		"microformat": map[string]interface{}{
+			"playerMicroformatRenderer": map[string]interface{}{"publishDate": "2009-10-24T23:57:33-07:00"},
+		},
+	}
+	expected := &yt_transcript_models.VideoMetadata{
+		VideoID:     "abc123",
+		Title:       "Player Title",
+		ChannelID:   "UC123",
+		ChannelName: "Test Channel",
+		Description: "A description",
+		Keywords:    []string{"go", "testing"},
+		Duration:    213,
+		PublishDate: time.Date(2009, 10, 24, 23, 57, 33, 0, time.FixedZone("", -7*60*60)),
+		ViewCount:   1500000000,
+		Thumbnails: []yt_transcript_models.Thumbnail{
+			{URL: "https://i.ytimg.com/vi/abc123/default.jpg", Width: 120, Height: 90},
+		},
+	}
+
+	t.Run("Without transcripts", func(t *testing.T) {
+		fetcher := &fixtures.MockHTMLFetcher{}
+		fetcher.On("FetchVideo", "abc123").Return([]byte(`<title>Page Title - YouTube</title>"INNERTUBE_API_KEY":"test_api_key"`), nil)
+		fetcher.On("FetchInnertubeData", "abc123", "test_api_key").Return(playerResponse, nil)
+
+		metadata, err := NewTranscriptService(fetcher).GetVideoMetadata(context.Background(), "abc123")
+
+		assert.NoError(t, err)
+		assert.True(t, expected.PublishDate.Equal(metadata.PublishDate))
+		metadata.PublishDate = expected.PublishDate
+		assert.Equal(t, expected, metadata)
+	})
+
+	t.Run("Alongside transcripts", func(t *testing.T) {
+		withCaptions := maps.Clone(playerResponse)
+		withCaptions["captions"] = map[string]interface{}{
+			"playerCaptionsTracklistRenderer": map[string]interface{}{
+				"captionTracks": []interface{}{
+					map[string]interface{}{"baseUrl": "http://example.com/en", "languageCode": "en"},
+				},
+			},
+		}
+
+		fetcher := &fixtures.MockHTMLFetcher{}
+		fetcher.On("FetchVideo", "abc123").Return([]byte(`<title>Page Title - YouTube</title>"INNERTUBE_API_KEY":"test_api_key"`), nil)
+		fetcher.On("FetchInnertubeData", "abc123", "test_api_key").Return(withCaptions, nil)
+		fetcher.On("FetchWithContext", mock.Anything, "http://example.com/en", mock.Anything).Return([]byte(`<transcript><text start="0" dur="1">Hello</text></transcript>`), nil)
+
+		transcripts, err := NewTranscriptService(fetcher).GetTranscripts("abc123", []string{"en"}, false)
+
+		assert.NoError(t, err)
+		assert.Len(t, transcripts, 1)
+		assert.Equal(t, "Player Title", transcripts[0].VideoTitle)
+		assert.Equal(t, "UC123", transcripts[0].Metadata.ChannelID)
+	})
+
+	t.Run("Page title without suffix", func(t *testing.T) {
+		assert.Equal(t, "Page Title", videoTitle(nil, "Page Title - YouTube"))
+		assert.Equal(t, "Player Title", videoTitle(expected, "Page Title - YouTube"))
+	})
+
+	t.Run("Unplayable video", func(t *testing.T) {
+		fetcher := &fixtures.MockHTMLFetcher{}
+		fetcher.On("FetchVideo", "abc123").Return([]byte(`"INNERTUBE_API_KEY":"test_api_key"`), nil)
+		fetcher.On("FetchInnertubeData", "abc123", "test_api_key").Return(map[string]interface{}{
+			"playabilityStatus": map[string]interface{}{"status": "LOGIN_REQUIRED", "reason": "This video is private"},
+		}, nil)
+
+		_, err := NewTranscriptService(fetcher).GetVideoMetadata(context.Background(), "abc123")
+
+		assert.ErrorIs(t, err, yt_errors.ErrVideoPrivate)
+	})
+}
+
+func TestGetTranslatedTranscripts(t *testing.T) {
+	innertubeData := func(translatable bool) map[string]interface{} {
+		return map[string]interface{}{
+			"captions": map[string]interface{}{
+				"playerCaptionsTracklistRenderer": map[string]interface{}{
+					"captionTracks": []interface{}{
+						map[string]interface{}{
+							"baseUrl":        "http://example.com/transcript?v=abc123&lang=de",
+							"name":           map[string]interface{}{"simpleText": "German"},
+							"languageCode":   "de",
+							"isTranslatable": translatable,
+						},
+					},
+					"translationLanguages": []interface{}{
+						map[string]interface{}{
+							"languageCode": "en",
+							"languageName": map[string]interface{}{"simpleText": "English"},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("Translates track", func(t *testing.T) {
+		fetcher := &fixtures.MockHTMLFetcher{}
+		fetcher.On("FetchVideo", "abc123").Return([]byte(`<title>Test Video</title>"INNERTUBE_API_KEY":"test_api_key"`), nil)
+		fetcher.On("FetchInnertubeData", "abc123", "test_api_key").Return(innertubeData(true), nil)
+		fetcher.On("FetchWithContext", mock.Anything, "http://example.com/transcript?lang=de&tlang=en&v=abc123", mock.Anything).
+			Return([]byte(`<transcript><text start="0" dur="1">Hello</text></transcript>`), nil)
+
+		service := NewTranscriptService(fetcher)
+		result, err := service.GetTranslatedTranscriptsWithContext(context.Background(), "abc123", []string{"de"}, "en", false)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+		assert.Equal(t, "en", result[0].LanguageCode)
+		assert.Equal(t, "English", result[0].Language)
+		assert.Equal(t, "Hello", result[0].Lines[0].Text)
+		fetcher.AssertExpectations(t)
+	})
+
+	t.Run("Track is not translatable", func(t *testing.T) {
+		fetcher := &fixtures.MockHTMLFetcher{}
+		fetcher.On("FetchVideo", "abc123").Return([]byte(`<title>Test Video</title>"INNERTUBE_API_KEY":"test_api_key"`), nil)
+		fetcher.On("FetchInnertubeData", "abc123", "test_api_key").Return(innertubeData(false), nil)
+
+		service := NewTranscriptService(fetcher)
+		_, err := service.GetTranslatedTranscriptsWithContext(context.Background(), "abc123", []string{"de"}, "en", false)
+
+		assert.ErrorIs(t, err, yt_errors.ErrNotTranslatable)
+	})
+
+	t.Run("Translation language not offered", func(t *testing.T) {
+		fetcher := &fixtures.MockHTMLFetcher{}
+		fetcher.On("FetchVideo", "abc123").Return([]byte(`<title>Test Video</title>"INNERTUBE_API_KEY":"test_api_key"`), nil)
+		fetcher.On("FetchInnertubeData", "abc123", "test_api_key").Return(innertubeData(true), nil)
+
+		service := NewTranscriptService(fetcher)
+		_, err := service.GetTranslatedTranscriptsWithContext(context.Background(), "abc123", []string{"de"}, "xx", false)
+
+		var notAvailable *yt_errors.TranslationLanguageNotAvailableError
+		assert.True(t, errors.As(err, &notAvailable))
+		assert.Equal(t, []string{"en"}, notAvailable.Available)
+	})
+}
+
+func TestTrackSelection(t *testing.T) {
+	asr := "asr"
+	transcripts := yt_transcript_models.TranscriptData{
+		CaptionTracks: []yt_transcript_models.CaptionTrack{
+			{LanguageCode: "en", BaseUrl: "en-manual"},
+			{LanguageCode: "en", BaseUrl: "en-asr", Kind: &asr},
+			{LanguageCode: "de", BaseUrl: "de-asr", Kind: &asr},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		selection TrackSelection
+		languages []string
+		expected  []string
+	}{
+		{"Any track", AnyTrack, []string{"en", "de"}, []string{"en-manual", "en-asr", "de-asr"}},
+		{"Manual only", ManualOnly, []string{"en", "de"}, []string{"en-manual"}},
+		{"Generated only", GeneratedOnly, []string{"en", "de"}, []string{"en-asr", "de-asr"}},
+		{"Prefer manual", PreferManual, []string{"en", "de"}, []string{"en-manual", "de-asr"}},
+		{"Prefer manual without languages", PreferManual, nil, []string{"en-manual", "de-asr"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewTranscriptService(&fixtures.MockHTMLFetcher{}, WithTrackSelection(tt.selection))
+			result, err := service.getTranscriptsForLanguage(tt.languages, transcripts)
+			assert.NoError(t, err)
+
+			urls := make([]string, 0, len(result))
+			for _, track := range result {
+				urls = append(urls, track.BaseUrl)
+			}
+			assert.Equal(t, tt.expected, urls)
+		})
+	}
+
+	t.Run("Fallback prefers the manual track", func(t *testing.T) {
+		tracks := yt_transcript_models.TranscriptData{
+			CaptionTracks: []yt_transcript_models.CaptionTrack{
+				{LanguageCode: "en", BaseUrl: "en-asr", Kind: &asr},
+				{LanguageCode: "en-GB", BaseUrl: "en-GB-manual"},
+				{LanguageCode: "en", BaseUrl: "en-manual"},
+			},
+		}
+
+		service := NewTranscriptService(&fixtures.MockHTMLFetcher{}, WithLanguageFallback(true))
+		result, err := service.getTranscriptsForLanguage([]string{"fr", "en"}, tracks)
+		assert.NoError(t, err)
+		assert.Equal(t, []yt_transcript_models.CaptionTrack{{LanguageCode: "en", BaseUrl: "en-manual"}}, result)
+	})
+
+	t.Run("Manual only without manual track", func(t *testing.T) {
+		service := NewTranscriptService(&fixtures.MockHTMLFetcher{}, WithTrackSelection(ManualOnly))
+		_, err := service.getTranscriptsForLanguage([]string{"de"}, transcripts)
+		assert.ErrorIs(t, err, yt_errors.ErrNoTranscript)
+	})
+}
+
+func TestLanguageMatching(t *testing.T) {
+	transcripts := yt_transcript_models.TranscriptData{
+		CaptionTracks: []yt_transcript_models.CaptionTrack{
+			{LanguageCode: "en-US", BaseUrl: "en-US"},
+			{LanguageCode: "en-GB", BaseUrl: "en-GB"},
+			{LanguageCode: "de", BaseUrl: "de"},
+			{LanguageCode: "pt-BR", BaseUrl: "pt-BR"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		fallback  bool
+		languages []string
+		expected  []string
+	}{
+		{"Primary tag matches regional tracks", false, []string{"en"}, []string{"en-US", "en-GB"}},
+		{"Exact match wins over regional tracks", false, []string{"en-gb"}, []string{"en-GB"}},
+		{"Underscore separator", false, []string{"pt_BR"}, []string{"pt-BR"}},
+		{"Regional tag falls back to primary", false, []string{"de-AT"}, []string{"de"}},
+		{"Duplicates are removed", false, []string{"en-US", "en"}, []string{"en-US", "en-GB"}},
+		{"All languages without fallback", false, []string{"de", "en-US"}, []string{"de", "en-US"}},
+		{"Fallback returns first available", true, []string{"de", "en-US"}, []string{"de"}},
+		{"Fallback skips missing languages", true, []string{"fr", "en-US", "de"}, []string{"en-US"}},
+		{"Fallback picks a single regional track", true, []string{"en"}, []string{"en-US"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewTranscriptService(&fixtures.MockHTMLFetcher{}, WithLanguageFallback(tt.fallback))
+			result, err := service.getTranscriptsForLanguage(tt.languages, transcripts)
+			assert.NoError(t, err)
+
+			urls := make([]string, 0, len(result))
+			for _, track := range result {
+				urls = append(urls, track.BaseUrl)
+			}
+			assert.Equal(t, tt.expected, urls)
+		})
+	}
+}
+
+func TestGetTranscriptsUsesCache(t *testing.T) {
+	fetcher := &fixtures.MockHTMLFetcher{}
+	fetcher.On("FetchVideo", "abc123").Return([]byte(`<title>Test Video</title>"INNERTUBE_API_KEY":"test_api_key"`), nil).Once()
+	fetcher.On("FetchInnertubeData", "abc123", "test_api_key").Return(map[string]interface{}{
+		"captions": map[string]interface{}{
+			"playerCaptionsTracklistRenderer": map[string]interface{}{
+				"captionTracks": []interface{}{
+					map[string]interface{}{
+						"baseUrl":      "http://example.com/transcript",
+						"name":         map[string]interface{}{"simpleText": "English"},
+						"languageCode": "en",
+						"kind":         "asr",
+					},
+				},
+			},
+		},
+	}, nil).Once()
+	fetcher.On("FetchWithContext", mock.Anything, "http://example.com/transcript", mock.Anything).
+		Return([]byte(`<transcript><text start="0" dur="1">Hello</text></transcript>`), nil).Once()
+
+	cache := yt_transcript_cache.NewMemoryCache(10)
+	service := NewTranscriptService(fetcher, WithCache(cache))
+
+	first, err := service.GetTranscripts("abc123", []string{"en"}, false)
+	assert.NoError(t, err)
+
+	second, err := service.GetTranscripts("abc123", []string{"en"}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	_, ok := cache.Get("tracks/abc123")
+	assert.True(t, ok)
+	_, ok = cache.Get("captions/abc123/en/asr/original")
+	assert.True(t, ok)
+	fetcher.AssertExpectations(t)
+}
+
+func TestCaptionCacheKey(t *testing.T) {
+	asr := "asr"
+	assert.Equal(t, "captions/abc123/en/manual/original",
+		captionCacheKey("abc123", yt_transcript_models.CaptionTrack{LanguageCode: "en", BaseUrl: "http://example.com/?lang=en"}, repository.CaptionFormatXML))
+	assert.Equal(t, "captions/abc123/de/asr/en",
+		captionCacheKey("abc123", yt_transcript_models.CaptionTrack{LanguageCode: "en", Kind: &asr, BaseUrl: "http://example.com/?lang=de&tlang=en"}, repository.CaptionFormatXML))
+	assert.Equal(t, "captions/abc123/en/asr/original.json3",
+		captionCacheKey("abc123", yt_transcript_models.CaptionTrack{LanguageCode: "en", Kind: &asr, BaseUrl: "http://example.com/?lang=en"}, repository.CaptionFormatJSON3))
+}
+
+func TestCaptionFormatsShareFileCache(t *testing.T) {
+	cache, err := yt_transcript_cache.NewFileCache(t.TempDir())
+	assert.NoError(t, err)
+
+	fetcher := &fixtures.MockHTMLFetcher{}
+	fetcher.On("FetchWithContext", mock.Anything, "http://example.com/transcript", mock.Anything).
+		Return([]byte(`<transcript><text start="0" dur="1">Hello</text></transcript>`), nil).Once()
+	fetcher.On("FetchWithContext", mock.Anything, "http://example.com/transcript?fmt=srv3", mock.Anything).
+		Return([]byte(`<timedtext format="3"><body><p t="0" d="1000">Hello</p></body></timedtext>`), nil).Once()
+
+	track := yt_transcript_models.CaptionTrack{LanguageCode: "en", BaseUrl: "http://example.com/transcript"}
+	for _, format := range []repository.CaptionFormat{repository.CaptionFormatXML, repository.CaptionFormatSrv3} {
+		service := NewTranscriptService(fetcher, WithCache(cache), WithCaptionFormat(format))
+
+		// The second fetch of every format is served from the cache
+		for range 2 {
+			lines, err := service.getTranscriptFromTrackWithContext(context.Background(), "abc123", track, false)
+			assert.NoError(t, err)
+			assert.Equal(t, "Hello", lines[0].Text)
+		}
+
+		_, ok := cache.Get(captionCacheKey("abc123", track, format))
+		assert.True(t, ok, string(format))
+	}
+	fetcher.AssertExpectations(t)
+}
+
+func TestCaptionURL(t *testing.T) {
+	baseURL := "http://example.com/api/timedtext?v=abc123&lang=en&fmt=srv3"
+
+	assert.Equal(t, "http://example.com/api/timedtext?lang=en&v=abc123", captionURL(baseURL, repository.CaptionFormatXML))
+	assert.Equal(t, "http://example.com/api/timedtext?fmt=json3&lang=en&v=abc123", captionURL(baseURL, repository.CaptionFormatJSON3))
+	assert.Equal(t, "http://example.com/en", captionURL("http://example.com/en", repository.CaptionFormatXML))
+}
+
 func TestSanitizeVideoID(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -284,3 +943,38 @@ func TestExtractTitle(t *testing.T) {
 		})
 	}
 }
+
+func TestStreamTrackLinesSkipsLinesOfRetriedDownload(t *testing.T) {
+	transcript := `<transcript><text start="0" dur="1">first</text><text start="1" dur="1">second</text><text start="2" dur="1">third</text></transcript>`
+
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) == 1 {
+			// The connection drops after the second line
+			w.Header().Set("Content-Length", "4096")
+			w.Write([]byte(transcript[:strings.Index(transcript, "<text start=\"2\"")]))
+			return
+		}
+		w.Write([]byte(transcript))
+	}))
+	defer server.Close()
+
+	fetcher := repository.NewHTMLFetcher(repository.WithRetryPolicy(repository.RetryPolicy{MaxAttempts: 2}))
+	cache := yt_transcript_cache.NewMemoryCache(10)
+	service := NewTranscriptService(fetcher, WithCache(cache))
+	track := yt_transcript_models.CaptionTrack{BaseUrl: server.URL, LanguageCode: "en"}
+
+	var texts []string
+	err := service.streamTrackLines(context.Background(), "test123", track, false, func(line yt_transcript_models.TranscriptLine) bool {
+		texts = append(texts, line.Text)
+		return true
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first", "second", "third"}, texts)
+	assert.Equal(t, int32(2), hits.Load())
+
+	body, ok := cache.Get(captionCacheKey("test123", track, repository.CaptionFormatXML))
+	assert.True(t, ok)
+	assert.Equal(t, transcript, string(body))
+}
@@ -1,13 +1,268 @@
 package errors
 
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
 type TranscriptError string
 
 func (e TranscriptError) Error() string {
 	return string(e)
 }
 
+// Sentinel errors. Every typed error below matches one of these with errors.Is,
+// so callers can either branch on the category or use errors.As for details.
 const (
-	ErrNoTranscript    = TranscriptError("no transcript found")
-	ErrInvalidVideoID  = TranscriptError("invalid video ID")
-	ErrTooManyRequests = TranscriptError("too many requests")
+	ErrNoTranscript        = TranscriptError("no transcript found")
+	ErrInvalidVideoID      = TranscriptError("invalid video ID")
+	ErrTooManyRequests     = TranscriptError("too many requests")
+	ErrVideoUnavailable    = TranscriptError("video unavailable")
+	ErrAgeRestricted       = TranscriptError("video is age restricted")
+	ErrLoginRequired       = TranscriptError("video requires sign in")
+	ErrVideoPrivate        = TranscriptError("video is private")
+	ErrVideoRemoved        = TranscriptError("video was removed")
+	ErrRegionBlocked       = TranscriptError("video is not available in this region")
+	ErrLiveStream          = TranscriptError("live stream has no transcripts")
+	ErrTranscriptsDisabled = TranscriptError("transcripts are disabled for this video")
+	ErrConsentFailed       = TranscriptError("failed to accept cookie consent")
+	ErrParseFailed         = TranscriptError("failed to parse response")
+	ErrNotTranslatable     = TranscriptError("transcript is not translatable")
+	ErrTranslationLanguage = TranscriptError("translation language not available")
+	ErrPlaylistUnavailable = TranscriptError("playlist unavailable")
+	ErrChannelNotFound     = TranscriptError("channel not found")
+	ErrNoProxyAvailable    = TranscriptError("no proxy available, all proxies were ejected")
 )
+
+// VideoUnavailableError is returned when YouTube reports the video as unavailable.
+type VideoUnavailableError struct {
+	VideoID string
+	Reason  string
+}
+
+func (e *VideoUnavailableError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("video %s is unavailable: %s", e.VideoID, e.Reason)
+	}
+	return fmt.Sprintf("video %s is unavailable", e.VideoID)
+}
+
+func (e *VideoUnavailableError) Is(target error) bool {
+	return target == ErrVideoUnavailable
+}
+
+// VideoPrivateError is returned for private videos. It also matches ErrVideoUnavailable.
+type VideoPrivateError struct {
+	VideoID string
+}
+
+func (e *VideoPrivateError) Error() string {
+	return fmt.Sprintf("video %s is private", e.VideoID)
+}
+
+func (e *VideoPrivateError) Is(target error) bool {
+	return target == ErrVideoPrivate || target == ErrVideoUnavailable
+}
+
+// VideoRemovedError is returned for videos removed by the uploader or by YouTube, e.g. for
+// a terms of service violation or a terminated account. It also matches ErrVideoUnavailable.
+type VideoRemovedError struct {
+	VideoID string
+	Reason  string
+}
+
+func (e *VideoRemovedError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("video %s was removed: %s", e.VideoID, e.Reason)
+	}
+	return fmt.Sprintf("video %s was removed", e.VideoID)
+}
+
+func (e *VideoRemovedError) Is(target error) bool {
+	return target == ErrVideoRemoved || target == ErrVideoUnavailable
+}
+
+// RegionBlockedError is returned when the video is not available in the country the request
+// came from, a proxy in another region may help. It also matches ErrVideoUnavailable.
+type RegionBlockedError struct {
+	VideoID string
+	Reason  string
+}
+
+func (e *RegionBlockedError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("video %s is blocked in this region: %s", e.VideoID, e.Reason)
+	}
+	return fmt.Sprintf("video %s is blocked in this region", e.VideoID)
+}
+
+func (e *RegionBlockedError) Is(target error) bool {
+	return target == ErrRegionBlocked || target == ErrVideoUnavailable
+}
+
+// LiveStreamError is returned for live streams, which have no transcripts until they have ended,
+// and for upcoming streams that have not started yet.
+type LiveStreamError struct {
+	VideoID string
+	Reason  string
+}
+
+func (e *LiveStreamError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("video %s is a live stream without transcripts: %s", e.VideoID, e.Reason)
+	}
+	return fmt.Sprintf("video %s is a live stream without transcripts", e.VideoID)
+}
+
+func (e *LiveStreamError) Is(target error) bool {
+	return target == ErrLiveStream
+}
+
+// AgeRestrictedError is returned when the video can only be watched after age verification.
+type AgeRestrictedError struct {
+	VideoID string
+}
+
+func (e *AgeRestrictedError) Error() string {
+	return fmt.Sprintf("video %s is age restricted", e.VideoID)
+}
+
+func (e *AgeRestrictedError) Is(target error) bool {
+	return target == ErrAgeRestricted
+}
+
+// LoginRequiredError is returned when the video can only be watched by a signed in account,
+// e.g. private or members-only videos. Reason is the message YouTube gave, if any.
+type LoginRequiredError struct {
+	VideoID string
+	Reason  string
+}
+
+func (e *LoginRequiredError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("video %s requires sign in: %s", e.VideoID, e.Reason)
+	}
+	return fmt.Sprintf("video %s requires sign in", e.VideoID)
+}
+
+func (e *LoginRequiredError) Is(target error) bool {
+	return target == ErrLoginRequired
+}
+
+// TranscriptsDisabledError is returned when the video is playable but has no caption tracks
+// at all, usually because the uploader disabled them.
+type TranscriptsDisabledError struct {
+	VideoID string
+}
+
+func (e *TranscriptsDisabledError) Error() string {
+	return fmt.Sprintf("transcripts are disabled for video %s by the uploader", e.VideoID)
+}
+
+func (e *TranscriptsDisabledError) Is(target error) bool {
+	return target == ErrTranscriptsDisabled
+}
+
+// NoTranscriptFoundError is returned when none of the requested languages is available.
+// Available holds the language codes of the tracks the video does have.
+type NoTranscriptFoundError struct {
+	VideoID            string
+	RequestedLanguages []string
+	Available          []string
+}
+
+func (e *NoTranscriptFoundError) Error() string {
+	return fmt.Sprintf("no transcript found for video %s in languages [%s], available: [%s]",
+		e.VideoID, strings.Join(e.RequestedLanguages, ", "), strings.Join(e.Available, ", "))
+}
+
+func (e *NoTranscriptFoundError) Is(target error) bool {
+	return target == ErrNoTranscript
+}
+
+// TooManyRequestsError is returned when YouTube answers with HTTP 429.
+// RetryAfter is zero when the response did not carry a Retry-After header.
+type TooManyRequestsError struct {
+	URL        string
+	RetryAfter time.Duration
+}
+
+func (e *TooManyRequestsError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("too many requests to %s, retry after %s", e.URL, e.RetryAfter)
+	}
+	return fmt.Sprintf("too many requests to %s", e.URL)
+}
+
+func (e *TooManyRequestsError) Is(target error) bool {
+	return target == ErrTooManyRequests
+}
+
+// ConsentError is returned when the cookie consent page could not be handled.
+type ConsentError struct {
+	Err error
+}
+
+func (e *ConsentError) Error() string {
+	return fmt.Sprintf("failed to accept cookie consent: %v", e.Err)
+}
+
+func (e *ConsentError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ConsentError) Is(target error) bool {
+	return target == ErrConsentFailed
+}
+
+// ParseError is returned when a YouTube response could not be parsed.
+// What describes the document being parsed, e.g. "transcript" or "innertube response".
+type ParseError struct {
+	What string
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("failed to parse %s: %v", e.What, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ParseError) Is(target error) bool {
+	return target == ErrParseFailed
+}
+
+// NotTranslatableError is returned when a translation was requested but none of the
+// matching caption tracks can be translated.
+type NotTranslatableError struct {
+	VideoID      string
+	LanguageCode string
+}
+
+func (e *NotTranslatableError) Error() string {
+	return fmt.Sprintf("transcript %s of video %s is not translatable", e.LanguageCode, e.VideoID)
+}
+
+func (e *NotTranslatableError) Is(target error) bool {
+	return target == ErrNotTranslatable
+}
+
+// TranslationLanguageNotAvailableError is returned when YouTube does not offer the
+// requested translation target. Available holds the language codes it does offer.
+type TranslationLanguageNotAvailableError struct {
+	VideoID      string
+	LanguageCode string
+	Available    []string
+}
+
+func (e *TranslationLanguageNotAvailableError) Error() string {
+	return fmt.Sprintf("translation to %s is not available for video %s, available: [%s]",
+		e.LanguageCode, e.VideoID, strings.Join(e.Available, ", "))
+}
+
+func (e *TranslationLanguageNotAvailableError) Is(target error) bool {
+	return target == ErrTranslationLanguage
+}
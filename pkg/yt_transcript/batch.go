@@ -0,0 +1,206 @@
+package yt_transcript
+
+import (
+	"context"
+	"iter"
+	"sync"
+	"time"
+
+	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_models"
+)
+
+const defaultBatchConcurrency = 4
+
+// BatchResult holds the outcome of a single video of a batch. Err is set when
+// fetching that video failed, the rest of the batch is unaffected.
+type BatchResult struct {
+	VideoID     string
+	Transcripts []yt_transcript_models.Transcript
+	Err         error
+}
+
+// BatchProgress is passed to the progress callback after every finished video.
+type BatchProgress struct {
+	Completed int
+	Total     int
+	Result    BatchResult
+}
+
+type batchConfig struct {
+	languages          []string
+	preserveFormatting bool
+	translateTo        string
+	concurrency        int
+	onProgress         func(BatchProgress)
+}
+
+type BatchOption func(*batchConfig)
+
+// WithBatchLanguages sets the languages fetched for every video of the batch.
+func WithBatchLanguages(languages ...string) BatchOption {
+	return func(b *batchConfig) {
+		b.languages = languages
+	}
+}
+
+// WithBatchPreserveFormatting keeps formatting tags such as <i> in the text, they are
+// stripped by default.
+func WithBatchPreserveFormatting(preserve bool) BatchOption {
+	return func(b *batchConfig) {
+		b.preserveFormatting = preserve
+	}
+}
+
+// WithBatchTranslation machine translates every transcript of the batch into language.
+func WithBatchTranslation(language string) BatchOption {
+	return func(b *batchConfig) {
+		b.translateTo = language
+	}
+}
+
+// WithConcurrency sets how many videos are fetched at the same time.
+func WithConcurrency(workers int) BatchOption {
+	return func(b *batchConfig) {
+		b.concurrency = workers
+	}
+}
+
+// WithProgress registers a callback invoked after every finished video.
+// Calls are serialized, the callback does not need to be safe for concurrent use.
+func WithProgress(onProgress func(BatchProgress)) BatchOption {
+	return func(b *batchConfig) {
+		b.onProgress = onProgress
+	}
+}
+
+// GetTranscriptsBatch fetches the transcripts of many videos with a bounded worker pool.
+// Results are returned in the order of videoIDs; a failing video only sets the Err of its
+// own result. The client Timeout applies to each video separately.
+func (c *YtTranscriptClient) GetTranscriptsBatch(ctx context.Context, videoIDs []string, options ...BatchOption) []BatchResult {
+	config := batchConfig{
+		preserveFormatting: preserve_formatting_default,
+		concurrency:        defaultBatchConcurrency,
+	}
+	for _, opt := range options {
+		opt(&config)
+	}
+	if config.concurrency < 1 {
+		config.concurrency = 1
+	}
+
+	results := make([]BatchResult, len(videoIDs))
+	jobs := make(chan int)
+
+	var (
+		wg        sync.WaitGroup
+		progress  sync.Mutex
+		completed int
+	)
+
+	for range min(config.concurrency, len(videoIDs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result := c.fetchBatchVideo(ctx, videoIDs[i], &config)
+				results[i] = result
+
+				if config.onProgress != nil {
+					progress.Lock()
+					completed++
+					config.onProgress(BatchProgress{Completed: completed, Total: len(videoIDs), Result: result})
+					progress.Unlock()
+				}
+			}
+		}()
+	}
+
+	for i := range videoIDs {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// All lazily ranges over the transcript lines of many videos. Videos are fetched one at a time
+// as the loop asks for more and lines are yielded while their caption track downloads, so a
+// slow loop body holds the download back instead of piling up transcripts in memory.
+//
+// A failing video yields a single error carrying its VideoID and the sequence moves on to the
+// next video; once ctx is done the sequence ends after yielding its error. The concurrency and
+// progress options don't apply. The client Timeout is not used since the time spent in the
+// loop body would count towards it, caption downloads only time out when YouTube stops sending.
+func (c *YtTranscriptClient) All(ctx context.Context, videoIDs []string, options ...BatchOption) iter.Seq2[yt_transcript_models.VideoLine, error] {
+	config := batchConfig{preserveFormatting: preserve_formatting_default}
+	for _, opt := range options {
+		opt(&config)
+	}
+
+	return func(yield func(yt_transcript_models.VideoLine, error) bool) {
+		for _, videoID := range videoIDs {
+			if err := ctx.Err(); err != nil {
+				yield(yt_transcript_models.VideoLine{VideoID: videoID}, err)
+				return
+			}
+
+			for line, err := range c.transcriptService.TranscriptLines(ctx, videoID, config.languages, config.translateTo, config.preserveFormatting) {
+				if !yield(line, err) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (c *YtTranscriptClient) fetchBatchVideo(ctx context.Context, videoID string, config *batchConfig) BatchResult {
+	if err := ctx.Err(); err != nil {
+		return BatchResult{VideoID: videoID, Err: err}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(c.Timeout)*time.Second)
+	defer cancel()
+
+	var (
+		transcripts []yt_transcript_models.Transcript
+		err         error
+	)
+	if config.translateTo != "" {
+		transcripts, err = c.transcriptService.GetTranslatedTranscriptsWithContext(ctx, videoID, config.languages, config.translateTo, config.preserveFormatting)
+	} else {
+		transcripts, err = c.transcriptService.GetTranscriptsWithContext(ctx, videoID, config.languages, config.preserveFormatting)
+	}
+
+	return BatchResult{VideoID: videoID, Transcripts: transcripts, Err: err}
+}
+
+// GetPlaylistVideoIDs returns the video IDs of a playlist, given its ID or URL.
+// Large playlists take one request per 100 videos, use ctx to bound the total time.
+func (c *YtTranscriptClient) GetPlaylistVideoIDs(ctx context.Context, playlist string) ([]string, error) {
+	return c.transcriptService.ListPlaylistVideos(ctx, playlist)
+}
+
+// GetChannelVideoIDs returns the IDs of the videos uploaded by a channel, given its
+// handle ("@name"), channel ID or URL.
+func (c *YtTranscriptClient) GetChannelVideoIDs(ctx context.Context, channel string) ([]string, error) {
+	return c.transcriptService.ListChannelVideos(ctx, channel)
+}
+
+// GetPlaylistTranscripts fetches the transcripts of every video of a playlist as a batch.
+func (c *YtTranscriptClient) GetPlaylistTranscripts(ctx context.Context, playlist string, options ...BatchOption) ([]BatchResult, error) {
+	videoIDs, err := c.GetPlaylistVideoIDs(ctx, playlist)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetTranscriptsBatch(ctx, videoIDs, options...), nil
+}
+
+// GetChannelTranscripts fetches the transcripts of every video uploaded by a channel as a batch.
+func (c *YtTranscriptClient) GetChannelTranscripts(ctx context.Context, channel string, options ...BatchOption) ([]BatchResult, error) {
+	videoIDs, err := c.GetChannelVideoIDs(ctx, channel)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetTranscriptsBatch(ctx, videoIDs, options...), nil
+}
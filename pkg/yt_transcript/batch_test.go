@@ -0,0 +1,165 @@
+package yt_transcript
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/horiagug/youtube-transcript-api-go/internal/repository/fixtures"
+)
+
+func mockVideo(fetcher *fixtures.MockHTMLFetcher, videoID string) {
+	mockVideoCaptions(fetcher, videoID, `<transcript><text start="0" dur="1">`+videoID+`</text></transcript>`)
+}
+
+func mockVideoCaptions(fetcher *fixtures.MockHTMLFetcher, videoID string, captions string) {
+	fetcher.On("FetchVideo", videoID).Return([]byte(`<title>`+videoID+`</title>"INNERTUBE_API_KEY":"test_api_key"`), nil)
+	fetcher.On("FetchInnertubeData", videoID, "test_api_key").Return(map[string]interface{}{
+		"captions": map[string]interface{}{
+			"playerCaptionsTracklistRenderer": map[string]interface{}{
+				"captionTracks": []interface{}{
+					map[string]interface{}{
+						"baseUrl":      "http://example.com/" + videoID,
+						"name":         map[string]interface{}{"simpleText": "English"},
+						"languageCode": "en",
+					},
+				},
+			},
+		},
+	}, nil)
+	fetcher.On("FetchWithContext", mock.Anything, "http://example.com/"+videoID, mock.Anything).
+		Return([]byte(captions), nil)
+}
+
+func TestGetTranscriptsBatch(t *testing.T) {
+	fetcher := &fixtures.MockHTMLFetcher{}
+	mockVideo(fetcher, "video1")
+	mockVideo(fetcher, "video3")
+	fetcher.On("FetchVideo", "video2").Return([]byte{}, errors.New("failed to fetch"))
+
+	client := NewClient(WithCustomFetcher(fetcher))
+
+	var progress []BatchProgress
+	results := client.GetTranscriptsBatch(context.Background(), []string{"video1", "video2", "video3"},
+		WithBatchLanguages("en"),
+		WithConcurrency(2),
+		WithProgress(func(p BatchProgress) {
+			progress = append(progress, p)
+		}),
+	)
+
+	assert.Len(t, results, 3)
+	assert.Equal(t, "video1", results[0].VideoID)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, "video1", results[0].Transcripts[0].Lines[0].Text)
+	assert.Equal(t, "video2", results[1].VideoID)
+	assert.Error(t, results[1].Err)
+	assert.Equal(t, "video3", results[2].VideoID)
+	assert.NoError(t, results[2].Err)
+	assert.Equal(t, "video3", results[2].Transcripts[0].Lines[0].Text)
+
+	assert.Len(t, progress, 3)
+	for i, p := range progress {
+		assert.Equal(t, i+1, p.Completed)
+		assert.Equal(t, 3, p.Total)
+	}
+}
+
+func TestGetTranscriptsBatchStripsFormatting(t *testing.T) {
+	fetcher := &fixtures.MockHTMLFetcher{}
+	mockVideoCaptions(fetcher, "video1", `<transcript><text start="0" dur="1">&lt;i&gt;video1&lt;/i&gt;</text></transcript>`)
+
+	client := NewClient(WithCustomFetcher(fetcher))
+
+	results := client.GetTranscriptsBatch(context.Background(), []string{"video1"})
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, "video1", results[0].Transcripts[0].Lines[0].Text)
+
+	results = client.GetTranscriptsBatch(context.Background(), []string{"video1"}, WithBatchPreserveFormatting(true))
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, "<i>video1</i>", results[0].Transcripts[0].Lines[0].Text)
+}
+
+func TestGetTranscriptsBatchCancelled(t *testing.T) {
+	client := NewClient(WithCustomFetcher(&fixtures.MockHTMLFetcher{}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := client.GetTranscriptsBatch(ctx, []string{"video1", "video2"})
+
+	assert.Len(t, results, 2)
+	for _, result := range results {
+		assert.ErrorIs(t, result.Err, context.Canceled)
+	}
+}
+
+func TestAll(t *testing.T) {
+	fetcher := &fixtures.MockHTMLFetcher{}
+	mockVideo(fetcher, "video1")
+	mockVideo(fetcher, "video3")
+	fetcher.On("FetchVideo", "video2").Return([]byte{}, errors.New("failed to fetch"))
+
+	client := NewClient(WithCustomFetcher(fetcher))
+
+	var texts []string
+	var failed []string
+	for line, err := range client.All(context.Background(), []string{"video1", "video2", "video3"}, WithBatchLanguages("en")) {
+		if err != nil {
+			failed = append(failed, line.VideoID)
+			continue
+		}
+		assert.Equal(t, "en", line.LanguageCode)
+		assert.Equal(t, line.VideoID, line.VideoTitle)
+		texts = append(texts, line.Text)
+	}
+
+	assert.Equal(t, []string{"video1", "video3"}, texts)
+	assert.Equal(t, []string{"video2"}, failed)
+}
+
+func TestAllStopsFetching(t *testing.T) {
+	fetcher := &fixtures.MockHTMLFetcher{}
+	mockVideo(fetcher, "video1")
+
+	client := NewClient(WithCustomFetcher(fetcher))
+
+	for line, err := range client.All(context.Background(), []string{"video1", "video2"}) {
+		assert.NoError(t, err)
+		assert.Equal(t, "video1", line.VideoID)
+		break
+	}
+
+	// video2 is never requested, the mock would panic otherwise
+	fetcher.AssertNotCalled(t, "FetchVideo", "video2")
+}
+
+func TestAllCancelled(t *testing.T) {
+	client := NewClient(WithCustomFetcher(&fixtures.MockHTMLFetcher{}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var errs []error
+	for _, err := range client.All(ctx, []string{"video1", "video2"}) {
+		errs = append(errs, err)
+	}
+
+	assert.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], context.Canceled)
+}
+
+func TestAllStripsFormatting(t *testing.T) {
+	fetcher := &fixtures.MockHTMLFetcher{}
+	mockVideoCaptions(fetcher, "video1", `<transcript><text start="0" dur="1">&lt;b&gt;video1&lt;/b&gt;</text></transcript>`)
+
+	client := NewClient(WithCustomFetcher(fetcher))
+
+	for line, err := range client.All(context.Background(), []string{"video1"}) {
+		assert.NoError(t, err)
+		assert.Equal(t, "video1", line.Text)
+	}
+}
@@ -2,17 +2,21 @@ package yt_transcript
 
 import (
 	"context"
-	"fmt"
+	"io"
 	"time"
 
 	"github.com/horiagug/youtube-transcript-api-go/internal/repository"
 	"github.com/horiagug/youtube-transcript-api-go/internal/service"
+	yt_errors "github.com/horiagug/youtube-transcript-api-go/pkg/errors"
 	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_formatters"
 	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_models"
 )
 
 type YtTranscriptClient struct {
 	transcriptService service.TranscriptService
+	fetcher           repository.HTMLFetcherType
+	fetcherOptions    []repository.FetcherOption
+	serviceOptions    []service.Option
 	Timeout           int
 	Formatter         yt_transcript_formatters.Formatter
 }
@@ -33,11 +37,12 @@ func NewClient(options ...Option) *YtTranscriptClient {
 		opt(client)
 	}
 
-	if client.transcriptService == nil {
-		fetcher := repository.NewHTMLFetcher()
-		client.transcriptService = service.NewTranscriptService(fetcher)
+	if client.fetcher == nil {
+		client.fetcher = repository.NewHTMLFetcher(client.fetcherOptions...)
 	}
 
+	client.transcriptService = service.NewTranscriptService(client.fetcher, client.serviceOptions...)
+
 	return client
 }
 
@@ -51,12 +56,43 @@ func (c *YtTranscriptClient) GetFormattedTranscripts(videoID string, languages [
 	}
 
 	if len(transcripts) == 0 {
-		return "", fmt.Errorf("No transcripts found")
+		return "", yt_errors.ErrNoTranscript
 	}
 
 	return c.Formatter.Format(transcripts)
 }
 
+// WriteFormattedTranscripts formats the transcripts of a video straight into w. Formatters
+// implementing StreamingFormatter write incrementally instead of building the output in memory.
+func (c *YtTranscriptClient) WriteFormattedTranscripts(w io.Writer, videoID string, languages []string, preserve_formatting bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(c.Timeout)*time.Second)
+	defer cancel()
+
+	transcripts, err := c.transcriptService.GetTranscriptsWithContext(ctx, videoID, languages, preserve_formatting)
+	if err != nil {
+		return err
+	}
+
+	if len(transcripts) == 0 {
+		return yt_errors.ErrNoTranscript
+	}
+
+	return c.writeFormatted(w, transcripts)
+}
+
+func (c *YtTranscriptClient) writeFormatted(w io.Writer, transcripts []yt_transcript_models.Transcript) error {
+	if streaming, ok := c.Formatter.(yt_transcript_formatters.StreamingFormatter); ok {
+		return streaming.WriteTo(w, transcripts)
+	}
+
+	formatted, err := c.Formatter.Format(transcripts)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, formatted)
+	return err
+}
+
 func (c *YtTranscriptClient) GetTranscripts(videoID string, languages []string) ([]yt_transcript_models.Transcript, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(c.Timeout)*time.Second)
 	defer cancel()
@@ -68,3 +104,34 @@ func (c *YtTranscriptClient) GetTranscripts(videoID string, languages []string)
 
 	return transcripts, nil
 }
+
+// GetTranslatedTranscripts fetches the first translatable transcript matching languages,
+// machine translated by YouTube into targetLanguage.
+func (c *YtTranscriptClient) GetTranslatedTranscripts(videoID string, languages []string, targetLanguage string, preserve_formatting bool) ([]yt_transcript_models.Transcript, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(c.Timeout)*time.Second)
+	defer cancel()
+
+	transcripts, err := c.transcriptService.GetTranslatedTranscriptsWithContext(ctx, videoID, languages, targetLanguage, preserve_formatting)
+	if err != nil {
+		return []yt_transcript_models.Transcript{}, err
+	}
+
+	return transcripts, nil
+}
+
+// ListTranscripts returns the caption tracks available for a video without downloading any of them.
+func (c *YtTranscriptClient) ListTranscripts(ctx context.Context, videoID string) (*yt_transcript_models.TranscriptList, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(c.Timeout)*time.Second)
+	defer cancel()
+
+	return c.transcriptService.ListTranscripts(ctx, videoID)
+}
+
+// GetVideoMetadata returns the title, channel, duration, publish date and other details of a
+// video. Unlike transcripts it is also available for videos whose captions are disabled.
+func (c *YtTranscriptClient) GetVideoMetadata(ctx context.Context, videoID string) (*yt_transcript_models.VideoMetadata, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(c.Timeout)*time.Second)
+	defer cancel()
+
+	return c.transcriptService.GetVideoMetadata(ctx, videoID)
+}
@@ -1,16 +1,67 @@
 package yt_transcript
 
 import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
 	"github.com/horiagug/youtube-transcript-api-go/internal/repository"
 	"github.com/horiagug/youtube-transcript-api-go/internal/service"
+	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_cache"
 	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_formatters"
 )
 
 type Option func(*YtTranscriptClient)
 
+// TrackSelection controls whether manually created or auto-generated tracks are used.
+type TrackSelection = service.TrackSelection
+
+const (
+	AnyTrack      = service.AnyTrack
+	ManualOnly    = service.ManualOnly
+	GeneratedOnly = service.GeneratedOnly
+	PreferManual  = service.PreferManual
+)
+
+// ProxyPool rotates requests over several proxies, ejecting those that keep getting rate limited.
+type ProxyPool = repository.ProxyPool
+
+type ProxyPoolOption = repository.ProxyPoolOption
+
+type ProxyStrategy = repository.ProxyStrategy
+
+const (
+	RoundRobin  = repository.RoundRobin
+	RandomProxy = repository.RandomProxy
+)
+
+// NewProxyPool creates a pool from http, https or socks5 proxy URLs.
+func NewProxyPool(proxyURLs []string, options ...ProxyPoolOption) (*ProxyPool, error) {
+	return repository.NewProxyPool(proxyURLs, options...)
+}
+
+// WithProxyStrategy selects round-robin (default) or random rotation.
+func WithProxyStrategy(strategy ProxyStrategy) ProxyPoolOption {
+	return repository.WithProxyStrategy(strategy)
+}
+
+// WithMaxProxyFailures sets after how many consecutive 429s or connection errors
+// a proxy is ejected from the pool, 3 by default.
+func WithMaxProxyFailures(failures int) ProxyPoolOption {
+	return repository.WithMaxProxyFailures(failures)
+}
+
+// ParseProxyURL parses and validates an http, https, socks5 or socks5h proxy URL.
+func ParseProxyURL(rawURL string) (*url.URL, error) {
+	return repository.ParseProxyURL(rawURL)
+}
+
+// WithCustomFetcher replaces the default fetcher. Options configuring the default
+// fetcher, such as proxies, are ignored when it is used.
 func WithCustomFetcher(fetcher repository.HTMLFetcherType) Option {
 	return func(c *YtTranscriptClient) {
-		c.transcriptService = service.NewTranscriptService(fetcher)
+		c.fetcher = fetcher
 	}
 }
 
@@ -24,3 +75,207 @@ func WithFormatter(formatter yt_transcript_formatters.Formatter) Option {
 		c.Formatter = formatter
 	}
 }
+
+// WithTrackSelection sets which kind of caption track is fetched, see TrackSelection.
+func WithTrackSelection(selection TrackSelection) Option {
+	return func(c *YtTranscriptClient) {
+		c.serviceOptions = append(c.serviceOptions, service.WithTrackSelection(selection))
+	}
+}
+
+// WithLanguageFallback treats the languages passed to the client as a priority list:
+// only the best track of the first available language is fetched instead of every matching
+// one, a manually created track over an auto-generated one.
+func WithLanguageFallback(enabled bool) Option {
+	return func(c *YtTranscriptClient) {
+		c.serviceOptions = append(c.serviceOptions, service.WithLanguageFallback(enabled))
+	}
+}
+
+// WithCache caches track lists and caption bodies, so fetching the same video again
+// doesn't hit YouTube. See yt_transcript_cache for the in-memory and file backends.
+func WithCache(cache yt_transcript_cache.Cache) Option {
+	return func(c *YtTranscriptClient) {
+		c.serviceOptions = append(c.serviceOptions, service.WithCache(cache))
+	}
+}
+
+// WithCacheTTL sets how long track lists and caption bodies stay cached, zero never expires.
+// Defaults to one hour for track lists, whose caption URLs expire, and a week for captions.
+func WithCacheTTL(trackList, captions time.Duration) Option {
+	return func(c *YtTranscriptClient) {
+		c.serviceOptions = append(c.serviceOptions, service.WithCacheTTL(trackList, captions))
+	}
+}
+
+// WithProxy routes every request through a single proxy, see ParseProxyURL.
+func WithProxy(proxyURL *url.URL) Option {
+	return func(c *YtTranscriptClient) {
+		c.fetcherOptions = append(c.fetcherOptions, repository.WithProxy(proxyURL))
+	}
+}
+
+// WithProxyPool rotates requests over the proxies of pool.
+func WithProxyPool(pool *ProxyPool) Option {
+	return func(c *YtTranscriptClient) {
+		c.fetcherOptions = append(c.fetcherOptions, repository.WithProxyPool(pool))
+	}
+}
+
+// CaptionFormat is the format caption tracks are downloaded in.
+type CaptionFormat = repository.CaptionFormat
+
+const (
+	CaptionFormatXML   = repository.CaptionFormatXML
+	CaptionFormatSrv3  = repository.CaptionFormatSrv3
+	CaptionFormatJSON3 = repository.CaptionFormatJSON3
+)
+
+// WithCaptionFormat downloads caption tracks as srv3 or json3 to get the timing of every word
+// of auto-generated tracks in TranscriptLine.Words. The legacy XML format is used by default.
+func WithCaptionFormat(format CaptionFormat) Option {
+	return func(c *YtTranscriptClient) {
+		c.serviceOptions = append(c.serviceOptions, service.WithCaptionFormat(format))
+	}
+}
+
+// WithSpans splits the text of every transcript line into TranscriptLine.Spans of bold,
+// italic and underlined text, which formatters render with WithSpanRenderer.
+func WithSpans(enabled bool) Option {
+	return func(c *YtTranscriptClient) {
+		c.serviceOptions = append(c.serviceOptions, service.WithSpans(enabled))
+	}
+}
+
+// ClientProfile is an innertube client impersonated by player requests.
+type ClientProfile = repository.ClientProfile
+
+var (
+	AndroidProfile    = repository.AndroidProfile
+	IOSProfile        = repository.IOSProfile
+	WebProfile        = repository.WebProfile
+	TVEmbeddedProfile = repository.TVEmbeddedProfile
+)
+
+// DefaultClientProfiles returns the profiles tried by default: Android, iOS, web and TV embedded.
+func DefaultClientProfiles() []ClientProfile {
+	return repository.DefaultClientProfiles()
+}
+
+// WithClientProfiles sets the innertube clients tried in order, falling back to the next one
+// when a client gets no captions or an unplayable video.
+func WithClientProfiles(profiles ...ClientProfile) Option {
+	return func(c *YtTranscriptClient) {
+		c.fetcherOptions = append(c.fetcherOptions, repository.WithClientProfiles(profiles...))
+	}
+}
+
+// LoadCookieJar reads a Netscape-format cookies.txt file, as exported by browser
+// extensions or yt-dlp, into a cookie jar for WithCookieJar.
+func LoadCookieJar(path string) (http.CookieJar, error) {
+	return repository.LoadCookieJar(path)
+}
+
+// WithCookieJar sends the cookies of jar with every request, so that age restricted and
+// members-only videos can be fetched with a signed in account.
+func WithCookieJar(jar http.CookieJar) Option {
+	return func(c *YtTranscriptClient) {
+		c.fetcherOptions = append(c.fetcherOptions, repository.WithCookieJar(jar))
+	}
+}
+
+// WithHTTPClient makes the default fetcher send every request with client,
+// e.g. one with a custom transport or timeout. Proxy options are ignored.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *YtTranscriptClient) {
+		c.fetcherOptions = append(c.fetcherOptions, repository.WithHTTPClient(client))
+	}
+}
+
+// WithRoundTripper makes the default fetcher send every request through roundTripper,
+// e.g. a tracing transport. Proxy options are ignored.
+func WithRoundTripper(roundTripper http.RoundTripper) Option {
+	return func(c *YtTranscriptClient) {
+		c.fetcherOptions = append(c.fetcherOptions, repository.WithRoundTripper(roundTripper))
+	}
+}
+
+// WithBaseURL sends the watch page and innertube requests to baseURL instead of
+// https://www.youtube.com, e.g. to the fake server of the ytfake package. Caption tracks are
+// fetched from the URLs the player response gives.
+func WithBaseURL(baseURL string) Option {
+	return func(c *YtTranscriptClient) {
+		c.fetcherOptions = append(c.fetcherOptions, repository.WithBaseURL(baseURL))
+	}
+}
+
+func WithUserAgent(userAgent string) Option {
+	return func(c *YtTranscriptClient) {
+		c.fetcherOptions = append(c.fetcherOptions, repository.WithUserAgent(userAgent))
+	}
+}
+
+// WithAcceptLanguage sets the Accept-Language header sent to YouTube, "en-US" by default.
+func WithAcceptLanguage(language string) Option {
+	return func(c *YtTranscriptClient) {
+		c.fetcherOptions = append(c.fetcherOptions, repository.WithAcceptLanguage(language))
+	}
+}
+
+// WithHeaders adds headers to every request sent to YouTube.
+func WithHeaders(headers http.Header) Option {
+	return func(c *YtTranscriptClient) {
+		c.fetcherOptions = append(c.fetcherOptions, repository.WithHeaders(headers))
+	}
+}
+
+// RetryPolicy controls how failed requests to YouTube are retried.
+type RetryPolicy = repository.RetryPolicy
+
+// DefaultRetryPolicy makes 3 attempts with exponential backoff from one second, retrying
+// 429 and 5xx responses and honoring Retry-After.
+func DefaultRetryPolicy() RetryPolicy {
+	return repository.DefaultRetryPolicy()
+}
+
+// WithRetryPolicy sets the retry policy of the default fetcher, applied to page,
+// innertube and caption requests alike.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *YtTranscriptClient) {
+		c.fetcherOptions = append(c.fetcherOptions, repository.WithRetryPolicy(policy))
+	}
+}
+
+// RateLimiter is a token bucket that can be shared by several clients.
+type RateLimiter = repository.RateLimiter
+
+func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	return repository.NewRateLimiter(requestsPerSecond, burst)
+}
+
+// NewPerHostRateLimiter keeps a separate bucket for every host, e.g. www.youtube.com.
+func NewPerHostRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	return repository.NewPerHostRateLimiter(requestsPerSecond, burst)
+}
+
+// WithRateLimit throttles all requests of the client to requestsPerSecond with
+// bursts of up to burst requests, shared by all concurrent and batch fetches.
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return WithRateLimiter(NewRateLimiter(requestsPerSecond, burst))
+}
+
+// WithRateLimiter throttles all requests of the client with limiter.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(c *YtTranscriptClient) {
+		c.fetcherOptions = append(c.fetcherOptions, repository.WithRateLimiter(limiter))
+	}
+}
+
+// WithLogger logs retries, consent handling, cache activity and failures of the client
+// to logger, with the video ID, attempt, status and URL as attributes. Nothing is logged by default.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *YtTranscriptClient) {
+		c.fetcherOptions = append(c.fetcherOptions, repository.WithLogger(logger))
+		c.serviceOptions = append(c.serviceOptions, service.WithLogger(logger))
+	}
+}
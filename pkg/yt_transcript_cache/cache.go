@@ -0,0 +1,27 @@
+package yt_transcript_cache
+
+import (
+	"time"
+)
+
+// Cache stores raw YouTube responses so repeated requests for the same video don't
+// hit YouTube again. Keys are "/" separated paths such as "tracks/<videoID>" or
+// "captions/<videoID>/<language>/<kind>/<translation>".
+//
+// A ttl of zero means the entry never expires. Implementations must be safe for
+// concurrent use.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration) error
+}
+
+func expiry(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
+
+func expired(expiresAt time.Time) bool {
+	return !expiresAt.IsZero() && time.Now().After(expiresAt)
+}
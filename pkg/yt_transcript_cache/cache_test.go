@@ -0,0 +1,93 @@
+package yt_transcript_cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewMemoryCache(2)
+
+	assert.NoError(t, cache.Set("a", []byte("1"), 0))
+	assert.NoError(t, cache.Set("b", []byte("2"), 0))
+
+	// Touch "a" so "b" becomes the least recently used entry
+	_, ok := cache.Get("a")
+	assert.True(t, ok)
+
+	assert.NoError(t, cache.Set("c", []byte("3"), 0))
+
+	_, ok = cache.Get("b")
+	assert.False(t, ok)
+	value, ok := cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+	assert.Equal(t, 2, cache.Len())
+}
+
+func TestMemoryCacheTTL(t *testing.T) {
+	cache := NewMemoryCache(10)
+
+	assert.NoError(t, cache.Set("short", []byte("1"), time.Millisecond))
+	assert.NoError(t, cache.Set("forever", []byte("2"), 0))
+	time.Sleep(5 * time.Millisecond)
+
+	_, ok := cache.Get("short")
+	assert.False(t, ok)
+	_, ok = cache.Get("forever")
+	assert.True(t, ok)
+}
+
+func TestMemoryCacheCopiesValues(t *testing.T) {
+	cache := NewMemoryCache(10)
+
+	value := []byte("abc")
+	assert.NoError(t, cache.Set("key", value, 0))
+	value[0] = 'x'
+
+	cached, ok := cache.Get("key")
+	assert.True(t, ok)
+	cached[1] = 'y'
+
+	cached, ok = cache.Get("key")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("abc"), cached)
+}
+
+func TestFileCache(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewFileCache(dir)
+	assert.NoError(t, err)
+
+	assert.NoError(t, cache.Set("captions/abc123/en/asr/original", []byte("<transcript/>"), 0))
+	assert.FileExists(t, filepath.Join(dir, "captions", "abc123", "en", "asr", "original"))
+
+	value, ok := cache.Get("captions/abc123/en/asr/original")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("<transcript/>"), value)
+
+	_, ok = cache.Get("captions/missing")
+	assert.False(t, ok)
+
+	assert.NoError(t, cache.Set("tracks/abc123", []byte("{}"), time.Millisecond))
+	time.Sleep(5 * time.Millisecond)
+	_, ok = cache.Get("tracks/abc123")
+	assert.False(t, ok)
+	assert.NoFileExists(t, filepath.Join(dir, "tracks", "abc123"))
+}
+
+func TestFileCacheStaysInsideDir(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewFileCache(filepath.Join(dir, "cache"))
+	assert.NoError(t, err)
+
+	assert.NoError(t, cache.Set("../escape", []byte("x"), 0))
+
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
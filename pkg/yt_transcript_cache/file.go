@@ -0,0 +1,95 @@
+package yt_transcript_cache
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FileCache stores every entry in its own file below Dir, mirroring the "/" separated
+// key, so a corpus can be inspected or pruned with regular file tools.
+type FileCache struct {
+	Dir string
+}
+
+// Each file starts with the expiry as unix nanoseconds, zero for entries that never expire
+const fileHeaderSize = 8
+
+func NewFileCache(dir string) (*FileCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return &FileCache{Dir: dir}, nil
+}
+
+func (c *FileCache) Get(key string) ([]byte, bool) {
+	path := c.path(key)
+	data, err := os.ReadFile(path)
+	if err != nil || len(data) < fileHeaderSize {
+		return nil, false
+	}
+
+	var expiresAt time.Time
+	if nanos := int64(binary.BigEndian.Uint64(data[:fileHeaderSize])); nanos != 0 {
+		expiresAt = time.Unix(0, nanos)
+	}
+	if expired(expiresAt) {
+		os.Remove(path)
+		return nil, false
+	}
+
+	return data[fileHeaderSize:], true
+}
+
+func (c *FileCache) Set(key string, value []byte, ttl time.Duration) error {
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	var nanos int64
+	if expiresAt := expiry(ttl); !expiresAt.IsZero() {
+		nanos = expiresAt.UnixNano()
+	}
+
+	data := make([]byte, fileHeaderSize+len(value))
+	binary.BigEndian.PutUint64(data, uint64(nanos))
+	copy(data[fileHeaderSize:], value)
+
+	// Write to a temporary file first so concurrent readers never see a partial entry
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	return nil
+}
+
+// path maps a key to a file below Dir, escaping every segment so keys can't leave the directory.
+func (c *FileCache) path(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segment = url.PathEscape(segment)
+		if segment == "" || segment == "." || segment == ".." {
+			segment = "_" + segment
+		}
+		segments[i] = segment
+	}
+	return filepath.Join(append([]string{c.Dir}, segments...)...)
+}
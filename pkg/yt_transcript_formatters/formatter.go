@@ -1,6 +1,10 @@
 package yt_transcript_formatters
 
 import (
+	"fmt"
+	"io"
+	"strings"
+
 	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_models"
 )
 
@@ -8,9 +12,18 @@ type Formatter interface {
 	Format(transcripts []yt_transcript_models.Transcript) (string, error)
 }
 
+// StreamingFormatter is a Formatter that can write its output directly to an io.Writer,
+// without building the whole document in memory first. All built-in formatters implement it.
+type StreamingFormatter interface {
+	Formatter
+	WriteTo(w io.Writer, transcripts []yt_transcript_models.Transcript) error
+}
+
 type BaseFormatter struct {
 	IncludeTimestamps   bool
 	IncludeLanguageCode bool
+	// SpanRenderer renders the spans of lines that have them, see WithSpanRenderer
+	SpanRenderer SpanRenderer
 }
 
 type FormatterOption func(f *BaseFormatter)
@@ -26,3 +39,36 @@ func WithLanguageCode(include bool) FormatterOption {
 		f.IncludeLanguageCode = include
 	}
 }
+
+// formatToString runs a streaming formatter into a string, used to implement Format.
+func formatToString(f StreamingFormatter, transcripts []yt_transcript_models.Transcript) (string, error) {
+	var text strings.Builder
+	if err := f.WriteTo(&text, transcripts); err != nil {
+		return "", err
+	}
+	return text.String(), nil
+}
+
+// errWriter remembers the first write error so formatters can check it once at the end.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (e *errWriter) printf(format string, args ...any) {
+	if e.err == nil {
+		_, e.err = fmt.Fprintf(e.w, format, args...)
+	}
+}
+
+func (e *errWriter) write(p []byte) {
+	if e.err == nil {
+		_, e.err = e.w.Write(p)
+	}
+}
+
+func (e *errWriter) writeString(s string) {
+	if e.err == nil {
+		_, e.err = io.WriteString(e.w, s)
+	}
+}
@@ -0,0 +1,246 @@
+package yt_transcript_formatters
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_models"
+)
+
+var testTranscripts = []yt_transcript_models.Transcript{
+	{
+		VideoID:      "abc123",
+		VideoTitle:   "Test Video",
+		Language:     "English",
+		LanguageCode: "en",
+		Lines: []yt_transcript_models.TranscriptLine{
+			{Text: "Hello world", Start: 0, Duration: 2.5},
+			{Text: "Overlapping line", Start: 2, Duration: 3},
+			{Text: "Last line", Start: 3661.0015, Duration: 1},
+		},
+	},
+}
+
+func TestSRTFormatter(t *testing.T) {
+	result, err := NewSRTFormatter().Format(testTranscripts)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1\n00:00:00,000 --> 00:00:02,000\nHello world\n\n"+
+		"2\n00:00:02,000 --> 00:00:05,000\nOverlapping line\n\n"+
+		"3\n01:01:01,002 --> 01:01:02,002\nLast line\n\n", result)
+}
+
+var twoTranscripts = []yt_transcript_models.Transcript{
+	{
+		VideoTitle:   "Test Video",
+		Language:     "English",
+		LanguageCode: "en",
+		Lines:        []yt_transcript_models.TranscriptLine{{Text: "Hello", Start: 0, Duration: 1}, {Text: "world", Start: 1, Duration: 1}},
+	},
+	{
+		VideoTitle:   "Test Video",
+		Language:     "German",
+		LanguageCode: "de",
+		Lines:        []yt_transcript_models.TranscriptLine{{Text: "Hallo", Start: 0, Duration: 1}},
+	},
+}
+
+func TestSRTFormatterSeveralTranscripts(t *testing.T) {
+	result, err := NewSRTFormatter().Format(twoTranscripts)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1\n00:00:00,000 --> 00:00:01,000\nHello\n\n"+
+		"2\n00:00:01,000 --> 00:00:02,000\nworld\n\n"+
+		"3\n00:00:00,000 --> 00:00:01,000\nHallo\n\n", result)
+}
+
+func TestFormatTimestamp(t *testing.T) {
+	tests := []struct {
+		seconds  float64
+		expected string
+	}{
+		{0, "00:00:00,000"},
+		{1.2345, "00:00:01,235"},
+		{59.9999, "00:01:00,000"},
+		{36000, "10:00:00,000"},
+		{-1, "00:00:00,000"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, formatTimestamp(tt.seconds, ","))
+	}
+}
+
+func TestWebVTTFormatter(t *testing.T) {
+	formatter := NewWebVTTFormatter()
+	formatter.Configure(WithCueSettings("align:center"))
+
+	result, err := formatter.Format(testTranscripts)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "WEBVTT\nLanguage: en\n\nNOTE\nTitle: Test Video\nLanguage: English\n\n"+
+		"00:00:00.000 --> 00:00:02.000 align:center\nHello world\n\n"+
+		"00:00:02.000 --> 00:00:05.000 align:center\nOverlapping line\n\n"+
+		"01:01:01.002 --> 01:01:02.002 align:center\nLast line\n\n", result)
+}
+
+func TestWebVTTFormatterSeveralTranscripts(t *testing.T) {
+	result, err := NewWebVTTFormatter().Format(twoTranscripts)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "WEBVTT\n\n"+
+		"NOTE\nTitle: Test Video\nLanguage: English (en)\n\n"+
+		"00:00:00.000 --> 00:00:01.000\nHello\n\n"+
+		"00:00:01.000 --> 00:00:02.000\nworld\n\n"+
+		"NOTE\nTitle: Test Video\nLanguage: German (de)\n\n"+
+		"00:00:00.000 --> 00:00:01.000\nHallo\n\n", result)
+}
+
+func TestWebVTTFormatterWithoutLanguageCode(t *testing.T) {
+	result, err := NewWebVTTFormatter(WithLanguageCode(false)).Format(testTranscripts[:1])
+
+	assert.NoError(t, err)
+	assert.Contains(t, result, "WEBVTT\n\n00:00:00.000 --> 00:00:02.000\n")
+	assert.NotContains(t, result, "NOTE")
+}
+
+func TestVTTText(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"plain text", "plain text"},
+		{"<b>bold</b> and <i>italic</i>", "<b>bold</b> and <i>italic</i>"},
+		{"<strong>strong</strong> <em>em</em>", "<b>strong</b> <i>em</i>"},
+		{`<font color="red">red</font>`, "red"},
+		{"1 < 2 & 3 > 2", "1 &lt; 2 &amp; 3 &gt; 2"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, vttText(tt.input))
+	}
+}
+
+func TestJSONFormatterWriteToMatchesMarshal(t *testing.T) {
+	transcripts := append(testTranscripts, yt_transcript_models.Transcript{
+		LanguageCode: "de",
+		Lines:        []yt_transcript_models.TranscriptLine{{Text: "Hallo <Welt>", Start: 1, Duration: 2}},
+	})
+
+	for _, pretty := range []bool{false, true} {
+		formatter := NewJSONFormatter()
+		formatter.Configure(WithPrettyPrint(pretty))
+
+		expected := make([]JSONTranscripts, 0, len(transcripts))
+		for _, transcript := range transcripts {
+			expected = append(expected, formatter.jsonTranscript(transcript))
+		}
+
+		var (
+			bytes []byte
+			err   error
+		)
+		if pretty {
+			bytes, err = json.MarshalIndent(expected, "", "  ")
+		} else {
+			bytes, err = json.Marshal(expected)
+		}
+		assert.NoError(t, err)
+
+		result, err := formatter.Format(transcripts)
+		assert.NoError(t, err)
+		assert.Equal(t, string(bytes), result)
+
+		result, err = formatter.Format(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "[]", result)
+	}
+}
+
+func TestJSONFormatterWords(t *testing.T) {
+	transcripts := []yt_transcript_models.Transcript{{
+		LanguageCode: "en",
+		Lines: []yt_transcript_models.TranscriptLine{{
+			Text:     "hello world",
+			Start:    1,
+			Duration: 2,
+			Words: []yt_transcript_models.Word{
+				{Text: "hello", Start: 1, Duration: 0.5},
+				{Text: "world", Start: 1.5, Duration: 1.5},
+			},
+		}},
+	}}
+
+	result, err := NewJSONFormatter().Format(transcripts)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{"language_code": "en", "transcripts": [{"text": "hello world", "start": 1, "duration": 2, "words": [
+		{"text": "hello", "start": 1, "duration": 0.5},
+		{"text": "world", "start": 1.5, "duration": 1.5}
+	]}]}]`, result)
+
+	result, err = NewJSONFormatter(WithTimestamps(false)).Format(transcripts)
+	assert.NoError(t, err)
+	assert.NotContains(t, result, "words")
+}
+
+func TestSpanRenderers(t *testing.T) {
+	spans := []yt_transcript_models.Span{
+		{Text: "Hello "},
+		{Text: "bold_word ", Bold: true},
+		{Text: "both", Bold: true, Italic: true},
+		{Text: " & "},
+		{Text: "under", Underline: true},
+	}
+
+	assert.Equal(t, `Hello **bold\_word** **_both_** & <u>under</u>`, MarkdownSpans(spans))
+	assert.Equal(t, "Hello <b>bold_word</b> <b><i>both</i></b> &amp; <u>under</u>", VTTSpans(spans))
+	assert.Equal(t, "Hello \x1b[1mbold_word\x1b[0m \x1b[1;3mboth\x1b[0m & \x1b[4munder\x1b[0m", ANSISpans(spans))
+}
+
+func TestFormattersRenderSpans(t *testing.T) {
+	transcripts := []yt_transcript_models.Transcript{{
+		Lines: []yt_transcript_models.TranscriptLine{{
+			Text:     "Hello world",
+			Duration: 1,
+			Spans:    []yt_transcript_models.Span{{Text: "Hello", Bold: true}, {Text: " world"}},
+		}},
+	}}
+
+	result, err := NewTextFormatter(WithTimestamps(false), WithSpanRenderer(MarkdownSpans)).Format(transcripts)
+	assert.NoError(t, err)
+	assert.Equal(t, "**Hello** world\n", result)
+
+	// Without a renderer the plain text is used
+	result, err = NewSRTFormatter().Format(transcripts)
+	assert.NoError(t, err)
+	assert.Contains(t, result, "\nHello world\n")
+
+	result, err = NewWebVTTFormatter().Format(transcripts)
+	assert.NoError(t, err)
+	assert.Contains(t, result, "\n<b>Hello</b> world\n")
+}
+
+func TestStreamingFormattersPropagateWriteErrors(t *testing.T) {
+	formatters := []StreamingFormatter{
+		NewJSONFormatter(),
+		NewTextFormatter(),
+		NewSRTFormatter(),
+		NewWebVTTFormatter(),
+	}
+
+	for _, formatter := range formatters {
+		err := formatter.WriteTo(failingWriter{}, testTranscripts)
+		assert.ErrorIs(t, err, errWriteFailed)
+	}
+}
+
+var errWriteFailed = errors.New("write failed")
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errWriteFailed
+}
@@ -2,6 +2,7 @@ package yt_transcript_formatters
 
 import (
 	"encoding/json"
+	"io"
 
 	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_models"
 )
@@ -10,6 +11,9 @@ type JSONTranscriptLine struct {
 	Text     string  `json:"text"`
 	Start    float64 `json:"start,omitempty"`
 	Duration float64 `json:"duration,omitempty"`
+	// Words is only set for srv3 and json3 auto-generated tracks when timestamps are included
+	Words []yt_transcript_models.Word `json:"words,omitempty"`
+	Spans []yt_transcript_models.Span `json:"spans,omitempty"`
 }
 
 type JSONTranscripts struct {
@@ -52,46 +56,80 @@ func (f *JSONFormatter) Configure(options ...JSONFormatterOption) {
 }
 
 func (f *JSONFormatter) Format(transcripts []yt_transcript_models.Transcript) (string, error) {
-	jsonTranscripts := make([]JSONTranscripts, len(transcripts))
+	return formatToString(f, transcripts)
+}
+
+// WriteTo writes the same JSON array as Format, encoding one transcript at a time.
+func (f *JSONFormatter) WriteTo(w io.Writer, transcripts []yt_transcript_models.Transcript) error {
+	if len(transcripts) == 0 {
+		_, err := io.WriteString(w, "[]")
+		return err
+	}
+
+	out := &errWriter{w: w}
+	separator := ","
+	if f.PrettyPrint {
+		out.writeString("[\n  ")
+		separator = ",\n  "
+	} else {
+		out.writeString("[")
+	}
 
 	for i, transcript := range transcripts {
-		lines := make([]JSONTranscriptLine, len(transcript.Lines))
-		for j, line := range transcript.Lines {
-			if f.IncludeTimestamps {
-				lines[j] = JSONTranscriptLine{
-					Text:     line.Text,
-					Start:    line.Start,
-					Duration: line.Duration,
-				}
-			} else {
-				lines[j] = JSONTranscriptLine{
-					Text: line.Text,
-				}
-			}
+		var (
+			bytes []byte
+			err   error
+		)
+
+		if f.PrettyPrint {
+			bytes, err = json.MarshalIndent(f.jsonTranscript(transcript), "  ", "  ")
+		} else {
+			bytes, err = json.Marshal(f.jsonTranscript(transcript))
 		}
 
-		jsonTranscripts[i] = JSONTranscripts{
-			Transcripts: lines,
+		if err != nil {
+			return err
 		}
-		if f.IncludeLanguageCode {
-			jsonTranscripts[i].LanguageCode = &transcript.LanguageCode
+
+		if i > 0 {
+			out.writeString(separator)
 		}
+		out.write(bytes)
 	}
 
-	var (
-		bytes []byte
-		err   error
-	)
-
 	if f.PrettyPrint {
-		bytes, err = json.MarshalIndent(jsonTranscripts, "", "  ")
+		out.writeString("\n]")
 	} else {
-		bytes, err = json.Marshal(jsonTranscripts)
+		out.writeString("]")
 	}
 
-	if err != nil {
-		return "", err
+	return out.err
+}
+
+func (f *JSONFormatter) jsonTranscript(transcript yt_transcript_models.Transcript) JSONTranscripts {
+	lines := make([]JSONTranscriptLine, len(transcript.Lines))
+	for j, line := range transcript.Lines {
+		if f.IncludeTimestamps {
+			lines[j] = JSONTranscriptLine{
+				Text:     line.Text,
+				Start:    line.Start,
+				Duration: line.Duration,
+				Words:    line.Words,
+				Spans:    line.Spans,
+			}
+		} else {
+			lines[j] = JSONTranscriptLine{
+				Text:  line.Text,
+				Spans: line.Spans,
+			}
+		}
 	}
 
-	return string(bytes), nil
+	result := JSONTranscripts{
+		Transcripts: lines,
+	}
+	if f.IncludeLanguageCode {
+		result.LanguageCode = &transcript.LanguageCode
+	}
+	return result
 }
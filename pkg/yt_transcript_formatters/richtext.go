@@ -0,0 +1,113 @@
+package yt_transcript_formatters
+
+import (
+	"strings"
+
+	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_models"
+)
+
+// SpanRenderer renders the formatted spans of a transcript line as text.
+type SpanRenderer func(spans []yt_transcript_models.Span) string
+
+// WithSpanRenderer renders lines that have spans with renderer instead of using their text.
+// The WebVTT formatter always renders spans as VTT tags and ignores it.
+func WithSpanRenderer(renderer SpanRenderer) FormatterOption {
+	return func(f *BaseFormatter) {
+		f.SpanRenderer = renderer
+	}
+}
+
+// lineText is the text of line as rendered by the span renderer, if any.
+func (f *BaseFormatter) lineText(line yt_transcript_models.TranscriptLine) string {
+	if f.SpanRenderer != nil && len(line.Spans) > 0 {
+		return f.SpanRenderer(line.Spans)
+	}
+	return line.Text
+}
+
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "<", `\<`)
+
+// MarkdownSpans renders bold as **text**, italic as _text_ and underline as <u>text</u>,
+// Markdown having no underline of its own.
+func MarkdownSpans(spans []yt_transcript_models.Span) string {
+	return renderSpans(spans, markdownEscaper.Replace, func(span yt_transcript_models.Span) (string, string) {
+		var open, close string
+		if span.Underline {
+			open, close = "<u>", "</u>"
+		}
+		if span.Bold {
+			open, close = open+"**", "**"+close
+		}
+		if span.Italic {
+			open, close = open+"_", "_"+close
+		}
+		return open, close
+	})
+}
+
+// VTTSpans renders spans with the <b>, <i> and <u> tags of WebVTT cues, escaping the text.
+func VTTSpans(spans []yt_transcript_models.Span) string {
+	return renderSpans(spans, escapeVTT, func(span yt_transcript_models.Span) (string, string) {
+		var open, close string
+		if span.Bold {
+			open, close = open+"<b>", "</b>"+close
+		}
+		if span.Italic {
+			open, close = open+"<i>", "</i>"+close
+		}
+		if span.Underline {
+			open, close = open+"<u>", "</u>"+close
+		}
+		return open, close
+	})
+}
+
+const ansiReset = "\x1b[0m"
+
+// ANSISpans renders spans with the ANSI escape codes of terminals for bold, italic and underline.
+func ANSISpans(spans []yt_transcript_models.Span) string {
+	return renderSpans(spans, nil, func(span yt_transcript_models.Span) (string, string) {
+		var codes []string
+		if span.Bold {
+			codes = append(codes, "1")
+		}
+		if span.Italic {
+			codes = append(codes, "3")
+		}
+		if span.Underline {
+			codes = append(codes, "4")
+		}
+		if len(codes) == 0 {
+			return "", ""
+		}
+		return "\x1b[" + strings.Join(codes, ";") + "m", ansiReset
+	})
+}
+
+// renderSpans writes every span's escaped text between the markup returned by wrap.
+// Leading and trailing spaces are kept outside the markup, which Markdown requires.
+func renderSpans(spans []yt_transcript_models.Span, escape func(string) string, wrap func(yt_transcript_models.Span) (string, string)) string {
+	var out strings.Builder
+	for _, span := range spans {
+		text := span.Text
+		if escape != nil {
+			text = escape(text)
+		}
+
+		open, close := wrap(span)
+		if open == "" {
+			out.WriteString(text)
+			continue
+		}
+
+		trimmed := strings.TrimSpace(text)
+		if trimmed == "" {
+			out.WriteString(text)
+			continue
+		}
+		leading := text[:strings.Index(text, trimmed)]
+		trailing := text[len(leading)+len(trimmed):]
+		out.WriteString(leading + open + trimmed + close + trailing)
+	}
+	return out.String()
+}
@@ -0,0 +1,81 @@
+package yt_transcript_formatters
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_models"
+)
+
+// SRTFormatter formats transcripts as SubRip (.srt) subtitles.
+// Timestamps are always included, so IncludeTimestamps and IncludeLanguageCode are ignored.
+type SRTFormatter struct {
+	BaseFormatter
+}
+
+func NewSRTFormatter(options ...FormatterOption) *SRTFormatter {
+	f := &SRTFormatter{
+		BaseFormatter: BaseFormatter{
+			IncludeTimestamps: true,
+		},
+	}
+
+	for _, opt := range options {
+		opt(&f.BaseFormatter)
+	}
+
+	return f
+}
+
+func (f *SRTFormatter) Format(transcripts []yt_transcript_models.Transcript) (string, error) {
+	return formatToString(f, transcripts)
+}
+
+func (f *SRTFormatter) WriteTo(w io.Writer, transcripts []yt_transcript_models.Transcript) error {
+	out := &errWriter{w: w}
+
+	// Cues are numbered across transcripts, an SRT file has a single sequence
+	cue := 0
+	for _, transcript := range transcripts {
+		for j, line := range transcript.Lines {
+			cue++
+			start, end := cueTimes(transcript.Lines, j)
+			out.printf("%d\n%s --> %s\n%s\n\n", cue, formatTimestamp(start, ","), formatTimestamp(end, ","), f.lineText(line))
+		}
+	}
+
+	return out.err
+}
+
+// cueTimes returns the start and end of the i-th line. Auto-generated captions overlap,
+// the next line often starting before the current one ends, so the end is clamped to the
+// next line's start to keep only one cue on screen at a time.
+func cueTimes(lines []yt_transcript_models.TranscriptLine, i int) (float64, float64) {
+	start := lines[i].Start
+	end := start + lines[i].Duration
+
+	if i+1 < len(lines) {
+		next := lines[i+1].Start
+		if next < end && next > start {
+			end = next
+		}
+	}
+
+	return start, end
+}
+
+// formatTimestamp formats seconds as HH:MM:SS followed by the separator and milliseconds.
+func formatTimestamp(seconds float64, separator string) string {
+	if seconds < 0 {
+		seconds = 0
+	}
+
+	total := int64(math.Round(seconds * 1000))
+	hours := total / 3_600_000
+	minutes := total / 60_000 % 60
+	secs := total / 1000 % 60
+	millis := total % 1000
+
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", hours, minutes, secs, separator, millis)
+}
@@ -1,8 +1,7 @@
 package yt_transcript_formatters
 
 import (
-	"fmt"
-	"strings"
+	"io"
 
 	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_models"
 )
@@ -27,11 +26,11 @@ func NewTextFormatter(options ...FormatterOption) *TextFormatter {
 }
 
 func (t *TextFormatter) Format(transcripts []yt_transcript_models.Transcript) (string, error) {
+	return formatToString(t, transcripts)
+}
 
-	var (
-		text strings.Builder
-		err  error
-	)
+func (t *TextFormatter) WriteTo(w io.Writer, transcripts []yt_transcript_models.Transcript) error {
+	out := &errWriter{w: w}
 
 	for i, transcript := range transcripts {
 		if t.IncludeLanguageCode {
@@ -43,30 +42,22 @@ func (t *TextFormatter) Format(transcripts []yt_transcript_models.Transcript) (s
 			}
 
 			if language != "" {
-				_, err = text.WriteString(fmt.Sprintf("Language: %s\n", language))
-			}
-
-			if err != nil {
-				return "", err
+				out.printf("Language: %s\n", language)
 			}
 		}
 
 		for _, line := range transcript.Lines {
 			if t.IncludeTimestamps {
-				_, err = text.WriteString(fmt.Sprintf("%f: %s\n", line.Start, line.Text))
+				out.printf("%f: %s\n", line.Start, t.lineText(line))
 			} else {
-				_, err = text.WriteString(line.Text + "\n")
+				out.writeString(t.lineText(line) + "\n")
 			}
 		}
 
 		if len(transcripts) > 1 && i < len(transcripts)-1 {
-			text.WriteString("\n")
+			out.writeString("\n")
 		}
 	}
 
-	if err != nil {
-		return "", err
-	}
-
-	return text.String(), nil
+	return out.err
 }
@@ -0,0 +1,140 @@
+package yt_transcript_formatters
+
+import (
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_models"
+)
+
+type WebVTTFormatterOption func(*WebVTTFormatter)
+
+// WebVTTFormatter formats transcripts as WebVTT (.vtt) subtitles.
+// IncludeLanguageCode adds the language to the header and a NOTE block with the video title,
+// several transcripts are written as one file with a NOTE block naming each of them.
+type WebVTTFormatter struct {
+	BaseFormatter
+	CueSettings string
+}
+
+// vttTags maps the formatting tags kept by preserve_formatting to their WebVTT equivalent.
+var vttTags = map[string]string{
+	"b":      "b",
+	"strong": "b",
+	"i":      "i",
+	"em":     "i",
+	"u":      "u",
+}
+
+var vttTagRegex = regexp.MustCompile(`<(/?)([a-zA-Z]+)[^>]*>`)
+
+func NewWebVTTFormatter(baseOptions ...FormatterOption) *WebVTTFormatter {
+	f := &WebVTTFormatter{
+		BaseFormatter: BaseFormatter{
+			IncludeTimestamps:   true,
+			IncludeLanguageCode: true,
+		},
+	}
+
+	for _, opt := range baseOptions {
+		opt(&f.BaseFormatter)
+	}
+	return f
+}
+
+// WithCueSettings appends settings such as "align:center line:90%" to every cue.
+func WithCueSettings(settings string) WebVTTFormatterOption {
+	return func(f *WebVTTFormatter) {
+		f.CueSettings = settings
+	}
+}
+
+func (f *WebVTTFormatter) Configure(options ...WebVTTFormatterOption) {
+	for _, opt := range options {
+		opt(f)
+	}
+}
+
+func (f *WebVTTFormatter) Format(transcripts []yt_transcript_models.Transcript) (string, error) {
+	return formatToString(f, transcripts)
+}
+
+func (f *WebVTTFormatter) WriteTo(w io.Writer, transcripts []yt_transcript_models.Transcript) error {
+	out := &errWriter{w: w}
+
+	settings := ""
+	if f.CueSettings != "" {
+		settings = " " + f.CueSettings
+	}
+
+	out.writeString("WEBVTT\n")
+	// The Language header describes the whole file, several transcripts name theirs in a NOTE
+	single := len(transcripts) == 1
+	if f.IncludeLanguageCode && single && transcripts[0].LanguageCode != "" {
+		out.printf("Language: %s\n", transcripts[0].LanguageCode)
+	}
+	out.writeString("\n")
+
+	for _, transcript := range transcripts {
+		if f.IncludeLanguageCode {
+			writeVTTNote(out, transcript, !single)
+		}
+
+		for j, line := range transcript.Lines {
+			start, end := cueTimes(transcript.Lines, j)
+			text := vttText(line.Text)
+			if len(line.Spans) > 0 {
+				text = VTTSpans(line.Spans)
+			}
+			out.printf("%s --> %s%s\n%s\n\n", formatTimestamp(start, "."), formatTimestamp(end, "."), settings, text)
+		}
+	}
+
+	return out.err
+}
+
+// writeVTTNote writes a NOTE block with the title and language of transcript, withCode adds
+// the language code, which the header holds otherwise.
+func writeVTTNote(out *errWriter, transcript yt_transcript_models.Transcript, withCode bool) {
+	language := transcript.Language
+	if withCode && transcript.LanguageCode != "" {
+		language = strings.TrimSpace(language + " (" + transcript.LanguageCode + ")")
+	}
+	if transcript.VideoTitle == "" && language == "" {
+		return
+	}
+
+	out.writeString("NOTE\n")
+	if transcript.VideoTitle != "" {
+		out.printf("Title: %s\n", strings.ReplaceAll(transcript.VideoTitle, "-->", "->"))
+	}
+	if language != "" {
+		out.printf("Language: %s\n", strings.ReplaceAll(language, "-->", "->"))
+	}
+	out.writeString("\n")
+}
+
+// vttText escapes cue text, keeping bold, italic and underline tags and dropping any other markup.
+func vttText(text string) string {
+	var out strings.Builder
+	last := 0
+
+	for _, m := range vttTagRegex.FindAllStringSubmatchIndex(text, -1) {
+		out.WriteString(escapeVTT(text[last:m[0]]))
+		last = m[1]
+
+		if tag, ok := vttTags[strings.ToLower(text[m[4]:m[5]])]; ok {
+			out.WriteString("<" + text[m[2]:m[3]] + tag + ">")
+		}
+	}
+	out.WriteString(escapeVTT(text[last:]))
+
+	return out.String()
+}
+
+var vttEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func escapeVTT(text string) string {
+	return vttEscaper.Replace(text)
+}
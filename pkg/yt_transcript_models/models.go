@@ -1,5 +1,7 @@
 package yt_transcript_models
 
+import "time"
+
 type Transcript struct {
 	VideoID        string
 	VideoTitle     string
@@ -8,17 +10,67 @@ type Transcript struct {
 	IsGenerated    bool
 	IsTranslatable bool
 	Lines          []TranscriptLine
+	// Metadata is shared by all transcripts of a video, nil when the player response had no video details
+	Metadata *VideoMetadata `json:",omitempty"`
 }
 
 type TranscriptLine struct {
 	Text     string  `json:"text"`
 	Start    float64 `json:"start"`
 	Duration float64 `json:"duration"`
+	// Words holds the timing of every word, only auto-generated tracks fetched as srv3 or json3 have it
+	Words []Word `json:"words,omitempty"`
+	// Spans splits Text into runs of bold, italic and underlined text, only set when requested
+	Spans []Span `json:"spans,omitempty"`
+}
+
+// VideoLine is a transcript line along with the video and caption track it belongs to, as
+// yielded when ranging over the lines of many videos. Index counts the lines of the track.
+type VideoLine struct {
+	VideoID      string
+	VideoTitle   string
+	Language     string
+	LanguageCode string
+	IsGenerated  bool
+	Index        int
+	TranscriptLine
+}
+
+// Span is a run of text sharing the same formatting.
+type Span struct {
+	Text      string `json:"text"`
+	Bold      bool   `json:"bold,omitempty"`
+	Italic    bool   `json:"italic,omitempty"`
+	Underline bool   `json:"underline,omitempty"`
+}
+
+// Word is a single word of a transcript line, Start and Duration are in seconds.
+type Word struct {
+	Text     string  `json:"text"`
+	Start    float64 `json:"start"`
+	Duration float64 `json:"duration"`
 }
 
+// TranscriptList describes the caption tracks available for a video without their content.
 type TranscriptList struct {
-	VideoID       string
+	VideoID string
+	// CaptionTracks holds the raw innertube caption tracks.
+	//
+	// Deprecated: use Transcripts instead.
 	CaptionTracks []CaptionTrack
+	VideoTitle    string
+	Transcripts   []AvailableTranscript
+	Metadata      *VideoMetadata `json:",omitempty"`
+}
+
+// AvailableTranscript describes a single caption track. TranslationLanguages lists the
+// languages the track can be machine translated into and is empty when it is not translatable.
+type AvailableTranscript struct {
+	Language             string
+	LanguageCode         string
+	IsGenerated          bool
+	IsTranslatable       bool
+	TranslationLanguages []LanguageData
 }
 
 type LanguageName struct {
@@ -55,8 +107,45 @@ type CaptionsDetails struct {
 type VideoTranscriptData struct {
 	Transcripts *TranscriptData
 	Title       string
+	Metadata    *VideoMetadata `json:",omitempty"`
+}
+
+// VideoMetadata describes a video as reported by the videoDetails and microformat sections
+// of the player response. Fields YouTube did not report are left empty.
+type VideoMetadata struct {
+	VideoID     string   `json:"videoId"`
+	Title       string   `json:"title"`
+	ChannelID   string   `json:"channelId"`
+	ChannelName string   `json:"channelName"`
+	Description string   `json:"description"`
+	Keywords    []string `json:"keywords,omitempty"`
+	// Duration is in seconds, like the start and duration of transcript lines
+	Duration float64 `json:"duration"`
+	// PublishDate is zero when unknown, only the web client reports it
+	PublishDate   time.Time   `json:"publishDate"`
+	ViewCount     int64       `json:"viewCount"`
+	Thumbnails    []Thumbnail `json:"thumbnails,omitempty"`
+	IsLive        bool        `json:"isLive"`
+	IsLiveContent bool        `json:"isLiveContent"`
+}
+
+type Thumbnail struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// PlayabilityStatus tells whether a video can be played, and why not, as reported by the player API.
+type PlayabilityStatus struct {
+	// Status is e.g. "OK", "ERROR", "UNPLAYABLE", "LOGIN_REQUIRED" or "LIVE_STREAM_OFFLINE"
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+	// ErrorScreen is the explanation shown below the reason, e.g. who removed the video
+	ErrorScreen string `json:"errorScreen,omitempty"`
 }
 
 type InnertubeData struct {
-	Captions CaptionsDetails `json:"captions"`
+	PlayabilityStatus PlayabilityStatus `json:"playabilityStatus"`
+	Captions          CaptionsDetails   `json:"captions"`
+	Metadata          *VideoMetadata    `json:"metadata,omitempty"`
 }
@@ -0,0 +1,119 @@
+package ytfake
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_models"
+)
+
+// segment is a piece of a caption line, offset is in milliseconds from the start of the line.
+type segment struct {
+	text   string
+	offset int64
+}
+
+func milliseconds(seconds float64) int64 {
+	return int64(math.Round(seconds * 1000))
+}
+
+func escapeXML(text string) string {
+	var escaped strings.Builder
+	xml.EscapeText(&escaped, []byte(text))
+	return escaped.String()
+}
+
+// segments splits the lines of generated tracks into timed words, like YouTube does in the
+// srv3 and json3 formats. Words without timing are spread evenly over the line.
+func segments(line yt_transcript_models.TranscriptLine, generated bool) []segment {
+	if !generated {
+		return []segment{{text: line.Text}}
+	}
+
+	if len(line.Words) > 0 {
+		result := make([]segment, 0, len(line.Words))
+		for i, word := range line.Words {
+			text := word.Text
+			if i > 0 {
+				text = " " + text
+			}
+			result = append(result, segment{text: text, offset: milliseconds(word.Start - line.Start)})
+		}
+		return result
+	}
+
+	words := strings.Fields(line.Text)
+	result := make([]segment, 0, len(words))
+	for i, word := range words {
+		if i > 0 {
+			word = " " + word
+		}
+		result = append(result, segment{text: word, offset: milliseconds(line.Duration) * int64(i) / int64(len(words))})
+	}
+	return result
+}
+
+// writeXML writes the legacy <transcript><text start=.. dur=..> format.
+func writeXML(w io.Writer, track Track) {
+	io.WriteString(w, `<?xml version="1.0" encoding="utf-8" ?><transcript>`)
+	for _, line := range track.Lines {
+		fmt.Fprintf(w, `<text start="%s" dur="%s">%s</text>`,
+			strconv.FormatFloat(line.Start, 'f', -1, 64),
+			strconv.FormatFloat(line.Duration, 'f', -1, 64),
+			escapeXML(line.Text))
+	}
+	io.WriteString(w, `</transcript>`)
+}
+
+// writeSrv3 writes the <timedtext><body><p t=.. d=..> format, words of generated tracks in <s t=..>.
+func writeSrv3(w io.Writer, track Track) {
+	io.WriteString(w, `<?xml version="1.0" encoding="utf-8" ?><timedtext format="3"><body>`)
+	for _, line := range track.Lines {
+		fmt.Fprintf(w, `<p t="%d" d="%d">`, milliseconds(line.Start), milliseconds(line.Duration))
+		if track.Generated {
+			for _, segment := range segments(line, true) {
+				fmt.Fprintf(w, `<s t="%d">%s</s>`, segment.offset, escapeXML(segment.text))
+			}
+		} else {
+			io.WriteString(w, escapeXML(line.Text))
+		}
+		io.WriteString(w, `</p>`)
+	}
+	io.WriteString(w, `</body></timedtext>`)
+}
+
+// writeJSON3 writes the {"events": [...]} format, segments of generated tracks carry a
+// recognition confidence.
+func writeJSON3(w io.Writer, track Track) {
+	type json3Segment struct {
+		Text       string   `json:"utf8"`
+		Offset     int64    `json:"tOffsetMs,omitempty"`
+		Confidence *float64 `json:"acAsrConf,omitempty"`
+	}
+	type json3Event struct {
+		Start    int64          `json:"tStartMs"`
+		Duration int64          `json:"dDurationMs"`
+		Segments []json3Segment `json:"segs"`
+	}
+
+	confidence := 1.0
+	events := make([]json3Event, 0, len(track.Lines))
+	for _, line := range track.Lines {
+		event := json3Event{Start: milliseconds(line.Start), Duration: milliseconds(line.Duration)}
+		for _, segment := range segments(line, track.Generated) {
+			jsonSegment := json3Segment{Text: segment.text, Offset: segment.offset}
+			if track.Generated {
+				jsonSegment.Confidence = &confidence
+			}
+			event.Segments = append(event.Segments, jsonSegment)
+		}
+		events = append(events, event)
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{"wireMagic": "pb3", "events": events})
+}
@@ -0,0 +1,422 @@
+// Package ytfake is a fake YouTube for testing code built on this module end to end without
+// network access. It serves watch pages, innertube player responses and caption tracks of the
+// videos added to it from an httptest.Server, and can require cookie consent or make any of
+// these requests fail:
+//
+//	server := ytfake.New(ytfake.WithVideos(ytfake.Video{
+//		ID:    "dQw4w9WgXcQ",
+//		Title: "Fake video",
+//		Tracks: []ytfake.Track{{
+//			LanguageCode: "en",
+//			Lines:        []yt_transcript_models.TranscriptLine{{Text: "Hello", Start: 0, Duration: 1.5}},
+//		}},
+//	}))
+//	defer server.Close()
+//
+//	client := yt_transcript.NewClient(yt_transcript.WithBaseURL(server.URL))
+package ytfake
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_models"
+)
+
+// APIKey is the innertube API key of the fake watch pages, player requests with any other
+// key are refused.
+const APIKey = "ytfake-api-key"
+
+// consentValue is the value of the consent form, accepted as "YES+" followed by it.
+const consentValue = "cb.20210328-17-p0.en+FX+000"
+
+// Route identifies a kind of request the server answers.
+type Route string
+
+const (
+	RouteWatch    Route = "watch"
+	RoutePlayer   Route = "player"
+	RouteCaptions Route = "captions"
+)
+
+// Video is a video served by the server. Videos that were not added are reported as
+// unavailable by the player.
+type Video struct {
+	ID          string
+	Title       string
+	ChannelID   string
+	ChannelName string
+	Description string
+	Duration    time.Duration
+	ViewCount   int64
+	PublishDate time.Time
+	IsLive      bool
+	// Status is the playability status, "OK" when empty. Reason is the message explaining
+	// any other status, e.g. "This video is private".
+	Status string
+	Reason string
+	Tracks []Track
+	// TranslationLanguages are the language codes translatable tracks can be translated into
+	TranslationLanguages []string
+}
+
+// Track is a caption track of a video, served in the format the request asks for.
+type Track struct {
+	LanguageCode string
+	// Name is the display name of the language, LanguageCode when empty
+	Name         string
+	Generated    bool
+	Translatable bool
+	// Lines are the content of the track. Lines of generated tracks are split into timed words
+	// in the srv3 and json3 formats, using the Words of the line when it has any.
+	Lines []yt_transcript_models.TranscriptLine
+	// Body is served instead of the lines whatever the format requested, e.g. a malformed track
+	Body string
+}
+
+// Failure makes requests of a route fail with Status instead of being answered.
+type Failure struct {
+	Status int
+	// Times is how many requests fail before the route recovers, zero fails all of them
+	Times int
+	// RetryAfter is sent in the Retry-After header of the failed responses when positive
+	RetryAfter time.Duration
+}
+
+// Server is the fake YouTube, it is started by New and must be closed when done.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	videos   map[string]Video
+	consent  bool
+	failures map[Route]*Failure
+	hits     map[Route]int
+}
+
+type Option func(*Server)
+
+// WithVideos adds videos to the server.
+func WithVideos(videos ...Video) Option {
+	return func(s *Server) {
+		for _, video := range videos {
+			s.videos[video.ID] = video
+		}
+	}
+}
+
+// WithConsent redirects watch page and player requests without an accepted CONSENT cookie to
+// a cookie consent page, as YouTube does for visitors from the EU.
+func WithConsent() Option {
+	return func(s *Server) {
+		s.consent = true
+	}
+}
+
+// New starts a server, its URL is the base URL to point the client at.
+func New(options ...Option) *Server {
+	s := &Server{
+		videos:   make(map[string]Video),
+		failures: make(map[Route]*Failure),
+		hits:     make(map[Route]int),
+	}
+	for _, opt := range options {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /watch", s.handle(RouteWatch, s.watch))
+	mux.HandleFunc("POST /youtubei/v1/player", s.handle(RoutePlayer, s.player))
+	mux.HandleFunc("GET /api/timedtext", s.handle(RouteCaptions, s.captions))
+	mux.HandleFunc("GET /consent", s.consentPage)
+
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// AddVideo adds a video to the running server, replacing the one with the same ID.
+func (s *Server) AddVideo(video Video) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.videos[video.ID] = video
+}
+
+// Fail makes the requests of route fail, a Failure without Status recovers the route.
+func (s *Server) Fail(route Route, failure Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if failure.Status == 0 {
+		delete(s.failures, route)
+		return
+	}
+	s.failures[route] = &failure
+}
+
+// Hits returns how many requests of route were received, failed ones included.
+func (s *Server) Hits(route Route) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.hits[route]
+}
+
+// handle counts the requests of route and answers them with the configured failure, if any.
+func (s *Server) handle(route Route, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if failure, ok := s.fail(route); ok {
+			if failure.RetryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(failure.RetryAfter.Seconds()))))
+			}
+			http.Error(w, http.StatusText(failure.Status), failure.Status)
+			return
+		}
+
+		if route != RouteCaptions && s.consent && !consented(r) {
+			http.Redirect(w, r, "/consent?continue="+url.QueryEscape(r.URL.String()), http.StatusFound)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+func (s *Server) fail(route Route) (Failure, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.hits[route]++
+	failure, ok := s.failures[route]
+	if !ok {
+		return Failure{}, false
+	}
+	if failure.Times > 0 {
+		failure.Times--
+		if failure.Times == 0 {
+			delete(s.failures, route)
+		}
+	}
+	return *failure, true
+}
+
+func (s *Server) video(videoID string) (Video, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	video, ok := s.videos[videoID]
+	return video, ok
+}
+
+func consented(r *http.Request) bool {
+	cookie, err := r.Cookie("CONSENT")
+	return err == nil && strings.HasPrefix(cookie.Value, "YES+")
+}
+
+// consentPage is a trimmed down version of the form of consent.youtube.com.
+func (s *Server) consentPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(w, `<!DOCTYPE html><html><head><title>Before you continue to YouTube</title></head><body>`+
+		`<form action="https://consent.youtube.com/s" method="POST">`+
+		`<input type="hidden" name="continue" value="%s">`+
+		`<input type="hidden" name="v" value="%s">`+
+		`<button>Accept all</button></form></body></html>`,
+		html.EscapeString(r.URL.Query().Get("continue")), consentValue)
+}
+
+func (s *Server) watch(w http.ResponseWriter, r *http.Request) {
+	title := "YouTube"
+	if video, ok := s.video(r.URL.Query().Get("v")); ok {
+		title = video.Title + " - YouTube"
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(w, `<!DOCTYPE html><html><head><title>%s</title></head><body>`+
+		`<script>ytcfg.set({"INNERTUBE_API_KEY": "%s"});</script></body></html>`,
+		html.EscapeString(title), APIKey)
+}
+
+func (s *Server) player(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("key") != APIKey {
+		http.Error(w, `{"error": {"code": 400, "message": "API key not valid"}}`, http.StatusBadRequest)
+		return
+	}
+
+	var request struct {
+		VideoID string `json:"videoId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, `{"error": {"code": 400, "message": "Invalid JSON payload"}}`, http.StatusBadRequest)
+		return
+	}
+
+	var response map[string]interface{}
+	if video, ok := s.video(request.VideoID); ok {
+		response = s.playerResponse(video)
+	} else {
+		response = map[string]interface{}{
+			"playabilityStatus": map[string]interface{}{"status": "ERROR", "reason": "This video is unavailable"},
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (s *Server) playerResponse(video Video) map[string]interface{} {
+	status := video.Status
+	if status == "" {
+		status = "OK"
+	}
+	playability := map[string]interface{}{"status": status}
+	if video.Reason != "" {
+		playability["reason"] = video.Reason
+	}
+	if status != "OK" {
+		return map[string]interface{}{"playabilityStatus": playability}
+	}
+
+	response := map[string]interface{}{
+		"playabilityStatus": playability,
+		"videoDetails": map[string]interface{}{
+			"videoId":          video.ID,
+			"title":            video.Title,
+			"channelId":        video.ChannelID,
+			"author":           video.ChannelName,
+			"shortDescription": video.Description,
+			"lengthSeconds":    strconv.Itoa(int(video.Duration.Seconds())),
+			"viewCount":        strconv.FormatInt(video.ViewCount, 10),
+			"isLive":           video.IsLive,
+			"isLiveContent":    video.IsLive,
+		},
+	}
+	if !video.PublishDate.IsZero() {
+		response["microformat"] = map[string]interface{}{
+			"playerMicroformatRenderer": map[string]interface{}{"publishDate": video.PublishDate.Format(time.RFC3339)},
+		}
+	}
+	if len(video.Tracks) == 0 {
+		return response
+	}
+
+	tracks := make([]interface{}, 0, len(video.Tracks))
+	for _, track := range video.Tracks {
+		query := url.Values{"v": {video.ID}, "lang": {track.LanguageCode}, "fmt": {"srv3"}}
+		captionTrack := map[string]interface{}{
+			"name":           map[string]interface{}{"simpleText": track.name()},
+			"languageCode":   track.LanguageCode,
+			"isTranslatable": track.Translatable,
+		}
+		if track.Generated {
+			query.Set("kind", "asr")
+			captionTrack["kind"] = "asr"
+		}
+		captionTrack["baseUrl"] = s.URL + "/api/timedtext?" + query.Encode()
+		tracks = append(tracks, captionTrack)
+	}
+
+	translationLanguages := make([]interface{}, 0, len(video.TranslationLanguages))
+	for _, language := range video.TranslationLanguages {
+		translationLanguages = append(translationLanguages, map[string]interface{}{
+			"languageCode": language,
+			"languageName": map[string]interface{}{"simpleText": language},
+		})
+	}
+
+	response["captions"] = map[string]interface{}{
+		"playerCaptionsTracklistRenderer": map[string]interface{}{
+			"captionTracks":        tracks,
+			"translationLanguages": translationLanguages,
+		},
+	}
+	return response
+}
+
+func (t Track) name() string {
+	if t.Name != "" {
+		return t.Name
+	}
+	return t.LanguageCode
+}
+
+// captions serves a caption track. Translations serve the lines of the source track with
+// their text prefixed by the target language, e.g. "[de] ".
+func (s *Server) captions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	video, ok := s.video(query.Get("v"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	track, ok := video.track(query.Get("lang"), query.Get("kind") == "asr")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	if target := query.Get("tlang"); target != "" {
+		if !track.Translatable || !video.translatableInto(target) {
+			http.NotFound(w, r)
+			return
+		}
+		track = track.translated(target)
+	}
+
+	if track.Body != "" {
+		w.Write([]byte(track.Body))
+		return
+	}
+
+	switch query.Get("fmt") {
+	case "srv3":
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		writeSrv3(w, track)
+	case "json3":
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		writeJSON3(w, track)
+	default:
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		writeXML(w, track)
+	}
+}
+
+func (v Video) track(languageCode string, generated bool) (Track, bool) {
+	for _, track := range v.Tracks {
+		if track.LanguageCode == languageCode && track.Generated == generated {
+			return track, true
+		}
+	}
+	return Track{}, false
+}
+
+func (v Video) translatableInto(languageCode string) bool {
+	for _, language := range v.TranslationLanguages {
+		if language == languageCode {
+			return true
+		}
+	}
+	return false
+}
+
+func (t Track) translated(languageCode string) Track {
+	lines := make([]yt_transcript_models.TranscriptLine, 0, len(t.Lines))
+	for _, line := range t.Lines {
+		lines = append(lines, yt_transcript_models.TranscriptLine{
+			Text:     "[" + languageCode + "] " + line.Text,
+			Start:    line.Start,
+			Duration: line.Duration,
+		})
+	}
+	t.LanguageCode = languageCode
+	t.Generated = false
+	t.Lines = lines
+	return t
+}
@@ -0,0 +1,191 @@
+package ytfake_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	yt_errors "github.com/horiagug/youtube-transcript-api-go/pkg/errors"
+	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript"
+	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_models"
+	"github.com/horiagug/youtube-transcript-api-go/pkg/ytfake"
+)
+
+var fastRetryPolicy = yt_transcript.RetryPolicy{
+	MaxAttempts:          3,
+	BaseDelay:            time.Millisecond,
+	MaxDelay:             10 * time.Millisecond,
+	RetryableStatusCodes: []int{http.StatusTooManyRequests, http.StatusInternalServerError},
+}
+
+var testVideo = ytfake.Video{
+	ID:          "dQw4w9WgXcQ",
+	Title:       "Fake video",
+	ChannelName: "Fake channel",
+	Duration:    4 * time.Second,
+	Tracks: []ytfake.Track{
+		{
+			LanguageCode: "en",
+			Name:         "English",
+			Translatable: true,
+			Lines: []yt_transcript_models.TranscriptLine{
+				{Text: "Hello & welcome", Start: 0, Duration: 1.5},
+				{Text: "to the fake", Start: 1.5, Duration: 2.5},
+			},
+		},
+		{
+			LanguageCode: "fr",
+			Name:         "French (auto-generated)",
+			Generated:    true,
+			Lines: []yt_transcript_models.TranscriptLine{
+				{Text: "bonjour tout le monde", Start: 1, Duration: 2},
+			},
+		},
+	},
+	TranslationLanguages: []string{"de"},
+}
+
+func newClient(server *ytfake.Server, options ...yt_transcript.Option) *yt_transcript.YtTranscriptClient {
+	options = append([]yt_transcript.Option{
+		yt_transcript.WithBaseURL(server.URL),
+		yt_transcript.WithRetryPolicy(fastRetryPolicy),
+	}, options...)
+	return yt_transcript.NewClient(options...)
+}
+
+func TestGetTranscripts(t *testing.T) {
+	server := ytfake.New(ytfake.WithVideos(testVideo))
+	defer server.Close()
+
+	transcripts, err := newClient(server).GetTranscripts(testVideo.ID, []string{"en"})
+	require.NoError(t, err)
+	require.Len(t, transcripts, 1)
+
+	assert.Equal(t, "Fake video", transcripts[0].VideoTitle)
+	assert.Equal(t, "Fake channel", transcripts[0].Metadata.ChannelName)
+	assert.Equal(t, []yt_transcript_models.TranscriptLine{
+		{Text: "Hello & welcome", Start: 0, Duration: 1.5},
+		{Text: "to the fake", Start: 1.5, Duration: 2.5},
+	}, transcripts[0].Lines)
+	assert.Equal(t, 1, server.Hits(ytfake.RouteCaptions))
+}
+
+func TestCaptionFormatsWordTiming(t *testing.T) {
+	server := ytfake.New(ytfake.WithVideos(testVideo))
+	defer server.Close()
+
+	for _, format := range []yt_transcript.CaptionFormat{yt_transcript.CaptionFormatSrv3, yt_transcript.CaptionFormatJSON3} {
+		t.Run(string(format), func(t *testing.T) {
+			client := newClient(server, yt_transcript.WithCaptionFormat(format))
+
+			transcripts, err := client.GetTranscripts(testVideo.ID, []string{"fr"})
+			require.NoError(t, err)
+			require.Len(t, transcripts[0].Lines, 1)
+
+			line := transcripts[0].Lines[0]
+			assert.Equal(t, "bonjour tout le monde", line.Text)
+			assert.Equal(t, []yt_transcript_models.Word{
+				{Text: "bonjour", Start: 1, Duration: 0.5},
+				{Text: "tout", Start: 1.5, Duration: 0.5},
+				{Text: "le", Start: 2, Duration: 0.5},
+				{Text: "monde", Start: 2.5, Duration: 0.5},
+			}, line.Words)
+		})
+	}
+}
+
+func TestTranslatedTranscripts(t *testing.T) {
+	server := ytfake.New(ytfake.WithVideos(testVideo))
+	defer server.Close()
+
+	transcripts, err := newClient(server).GetTranslatedTranscripts(testVideo.ID, []string{"en"}, "de", false)
+	require.NoError(t, err)
+	assert.Equal(t, "[de] Hello & welcome", transcripts[0].Lines[0].Text)
+
+	_, err = newClient(server).GetTranslatedTranscripts(testVideo.ID, []string{"en"}, "es", false)
+	assert.ErrorIs(t, err, yt_errors.ErrTranslationLanguage)
+}
+
+func TestConsent(t *testing.T) {
+	server := ytfake.New(ytfake.WithVideos(testVideo), ytfake.WithConsent())
+	defer server.Close()
+
+	transcripts, err := newClient(server).GetTranscripts(testVideo.ID, []string{"en"})
+	require.NoError(t, err)
+	assert.Equal(t, "Fake video", transcripts[0].VideoTitle)
+	assert.Len(t, transcripts[0].Lines, 2)
+
+	// The player request is redirected once before the consent cookie is sent along
+	assert.Equal(t, 2, server.Hits(ytfake.RoutePlayer))
+}
+
+func TestRateLimitedRequestsAreRetried(t *testing.T) {
+	server := ytfake.New(ytfake.WithVideos(testVideo))
+	defer server.Close()
+
+	server.Fail(ytfake.RoutePlayer, ytfake.Failure{Status: http.StatusTooManyRequests, Times: 1})
+	server.Fail(ytfake.RouteCaptions, ytfake.Failure{Status: http.StatusInternalServerError, Times: 2})
+
+	transcripts, err := newClient(server).GetTranscripts(testVideo.ID, []string{"en"})
+	require.NoError(t, err)
+	assert.Len(t, transcripts[0].Lines, 2)
+	assert.Equal(t, 2, server.Hits(ytfake.RoutePlayer))
+	assert.Equal(t, 3, server.Hits(ytfake.RouteCaptions))
+}
+
+func TestRateLimited(t *testing.T) {
+	server := ytfake.New(ytfake.WithVideos(testVideo))
+	defer server.Close()
+
+	server.Fail(ytfake.RouteWatch, ytfake.Failure{Status: http.StatusTooManyRequests})
+
+	_, err := newClient(server).GetTranscripts(testVideo.ID, []string{"en"})
+	assert.ErrorIs(t, err, yt_errors.ErrTooManyRequests)
+	assert.Equal(t, fastRetryPolicy.MaxAttempts, server.Hits(ytfake.RouteWatch))
+}
+
+func TestPlayabilityErrors(t *testing.T) {
+	server := ytfake.New(ytfake.WithVideos(
+		ytfake.Video{ID: "private0000", Status: "LOGIN_REQUIRED", Reason: "This video is private"},
+		ytfake.Video{ID: "nocaptions0", Title: "No captions"},
+	))
+	defer server.Close()
+
+	client := newClient(server)
+
+	_, err := client.GetTranscripts("private0000", nil)
+	assert.ErrorIs(t, err, yt_errors.ErrVideoPrivate)
+
+	_, err = client.GetTranscripts("nocaptions0", nil)
+	assert.ErrorIs(t, err, yt_errors.ErrTranscriptsDisabled)
+
+	_, err = client.GetTranscripts("unknown0000", nil)
+	assert.ErrorIs(t, err, yt_errors.ErrVideoUnavailable)
+}
+
+func TestMalformedTrack(t *testing.T) {
+	video := testVideo
+	video.Tracks = []ytfake.Track{{LanguageCode: "en", Body: "<transcript><text start="}}
+
+	server := ytfake.New(ytfake.WithVideos(video))
+	defer server.Close()
+
+	_, err := newClient(server).GetTranscripts(video.ID, []string{"en"})
+	assert.ErrorIs(t, err, yt_errors.ErrParseFailed)
+}
+
+func TestAllOverFakeServer(t *testing.T) {
+	server := ytfake.New(ytfake.WithVideos(testVideo))
+	defer server.Close()
+
+	var texts []string
+	for line, err := range newClient(server).All(context.Background(), []string{testVideo.ID}, yt_transcript.WithBatchLanguages("en")) {
+		require.NoError(t, err)
+		texts = append(texts, line.Text)
+	}
+	assert.Equal(t, []string{"Hello & welcome", "to the fake"}, texts)
+}